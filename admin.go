@@ -0,0 +1,93 @@
+package gogo
+
+import (
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/dolab/gogo/internal/listeners"
+)
+
+// adminGuard protects internal endpoints by token and allowlist of client IPs.
+//
+// NOTE: Only loopback clients are allowed if neither token nor allowlist given.
+type adminGuard struct {
+	token   string
+	allowed []*net.IPNet
+}
+
+func newAdminGuard(token string, allowed []string) (*adminGuard, error) {
+	nets, err := listeners.ParseCIDRs(allowed)
+	if err != nil {
+		return nil, err
+	}
+
+	return &adminGuard{
+		token:   token,
+		allowed: nets,
+	}, nil
+}
+
+// Allow returns true if both token and client IP of request are accepted.
+func (guard *adminGuard) Allow(r *http.Request) bool {
+	ip := guard.remoteIP(r)
+
+	if guard.token == "" && len(guard.allowed) == 0 {
+		return ip != nil && ip.IsLoopback()
+	}
+
+	if len(guard.allowed) > 0 {
+		if ip == nil {
+			return false
+		}
+
+		matched := false
+		for _, ipnet := range guard.allowed {
+			if ipnet.Contains(ip) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if guard.token != "" {
+		token := r.Header.Get(DefaultAdminTokenHeader)
+		if token == "" {
+			auth := r.Header.Get("Authorization")
+			if len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+				token = auth[7:]
+			}
+		}
+
+		return subtle.ConstantTimeCompare([]byte(token), []byte(guard.token)) == 1
+	}
+
+	return true
+}
+
+// Wrap returns http.Handler which responses 403 for requests denied.
+func (guard *adminGuard) Wrap(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !guard.Allow(r) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// remoteIP resolves IP of the peer, it's the source of PROXY protocol header
+// if present. Forwarded headers are ignored for security.
+func (guard *adminGuard) remoteIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return net.ParseIP(host)
+}
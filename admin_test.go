@@ -0,0 +1,55 @@
+package gogo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golib/assert"
+)
+
+func Test_adminGuard(t *testing.T) {
+	it := assert.New(t)
+
+	newRequest := func(remoteAddr, token string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, GogoDebug, nil)
+		r.RemoteAddr = remoteAddr
+		if token != "" {
+			r.Header.Set(DefaultAdminTokenHeader, token)
+		}
+
+		return r
+	}
+
+	// it should allow loopback only by default
+	guard, err := newAdminGuard("", nil)
+	if it.Nil(err) {
+		it.True(guard.Allow(newRequest("127.0.0.1:8080", "")))
+		it.True(guard.Allow(newRequest("[::1]:8080", "")))
+		it.False(guard.Allow(newRequest("10.0.0.1:8080", "")))
+	}
+
+	// it should check token
+	guard, err = newAdminGuard("s3cret", nil)
+	if it.Nil(err) {
+		it.True(guard.Allow(newRequest("10.0.0.1:8080", "s3cret")))
+		it.False(guard.Allow(newRequest("10.0.0.1:8080", "invalid")))
+		it.False(guard.Allow(newRequest("127.0.0.1:8080", "")))
+
+		r := newRequest("10.0.0.1:8080", "")
+		r.Header.Set("Authorization", "Bearer s3cret")
+		it.True(guard.Allow(r))
+	}
+
+	// it should check both of allowlist and token
+	guard, err = newAdminGuard("s3cret", []string{"10.0.0.0/8"})
+	if it.Nil(err) {
+		it.True(guard.Allow(newRequest("10.0.0.1:8080", "s3cret")))
+		it.False(guard.Allow(newRequest("10.0.0.1:8080", "")))
+		it.False(guard.Allow(newRequest("192.168.0.1:8080", "s3cret")))
+	}
+
+	// it should fail with invalid allowlist
+	_, err = newAdminGuard("", []string{"invalid"})
+	it.NotNil(err)
+}
@@ -10,6 +10,7 @@ namespace: {{.Namespace}}
 
 import (
 	"flag"
+	"log"
 	"os"
 	"path"
 
@@ -46,7 +47,10 @@ func main() {
 		cfgPath = path.Clean(cfgPath)
 	}
 
-	gogo.New(runMode, cfgPath).NewService(controllers.New()).Serve()
+	err := gogo.New(runMode, cfgPath).NewService(controllers.New()).Serve()
+	if err != nil {
+		log.Fatal(err)
+	}
 }
 `
 )
@@ -775,6 +775,7 @@ func (g *generator) genClientImplement(
 	g.P()
 	g.P(`// `, newClientFunc, ` creates a client that stubs the `, svcName, ` interface.`)
 	g.P(`// It communicates using protocol supplied and can be configured with a custom `, g.aliases["protocol"], `.HTTPClient.`)
+	g.P(`// Use `, g.aliases["protocol"], `.NewH2CClient() for multiplexed plaintext connections of h2c server.`)
 	g.P(`func `, newClientFunc, `(addr string, ptype `, g.aliases["protocol"], `.ProtocolType, clients ...`, g.aliases["protocol"], `.HTTPClient) `, svcName, ` {`)
 	g.P(`	// resolve protocol`)
 	g.P(`	proto, err := `, g.aliases["protocol"], `.New(ptype)`)
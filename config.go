@@ -4,13 +4,21 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 
+	"github.com/dolab/gogo/internal/listeners"
 	"github.com/dolab/gogo/pkgs/interceptors"
 	"github.com/dolab/logger"
 	yaml "gopkg.in/yaml.v2"
 )
 
+var (
+	network2addr = regexp.MustCompile(`(?i)^(http|https|tcp|tcp4|tcp6|unix|unixpacket):/{1,3}?(.+)?`)
+)
+
 // default configurations
 var (
 	DefaultServerConfig = &ServerConfig{
@@ -38,13 +46,14 @@ var (
 )
 
 // AppConfig defines config component of gogo.
-// It implements Configer interface.
+// It implements Configer and ReloadableConfiger interfaces.
 type AppConfig struct {
 	Mode     RunMode                 `yaml:"mode"`
 	Name     string                  `yaml:"name"`
 	Sections map[RunMode]interface{} `yaml:"sections"`
 
-	interceptors *InterceptorConfig
+	imux         sync.RWMutex
+	interceptors interceptors.Configer
 	filename     string
 }
 
@@ -165,18 +174,29 @@ func (config *AppConfig) UnmarshalYAML(v interface{}) error {
 
 // Interceptors returns a InterceptorConfiger wrapped with parsed YAML-encoded data of all middlewares.
 func (config *AppConfig) Interceptors() interceptors.Configer {
+	config.imux.RLock()
+	defer config.imux.RUnlock()
+
+	if config.interceptors == nil {
+		return (*InterceptorConfig)(nil)
+	}
+
 	return config.interceptors
 }
 
+// SetInterceptors replaces config of all middlewares, it's useful for rolling
+// back a failed reloading.
+func (config *AppConfig) SetInterceptors(ic interceptors.Configer) {
+	config.imux.Lock()
+	config.interceptors = ic
+	config.imux.Unlock()
+}
+
 // LoadInterceptors reads all config of interceptors
 func (config *AppConfig) LoadInterceptors() error {
-	mode := config.RunMode().String()
-	cfgfile := path.Dir(config.Filename())
-	cfgfile = strings.TrimSuffix(cfgfile, "/config")
-
-	filename := FindInterceptorConfigFile(mode, cfgfile)
+	filename := FindInterceptorConfigFile(config.RunMode().String(), config.interceptorsRoot())
 	if strings.HasPrefix(filename, GogoSchema) {
-		config.interceptors = DefaultInterceptorConfig
+		config.SetInterceptors(DefaultInterceptorConfig)
 		return nil
 	}
 
@@ -185,14 +205,33 @@ func (config *AppConfig) LoadInterceptors() error {
 		return err
 	}
 
-	err = yaml.Unmarshal(b, &config.interceptors)
+	var ic *InterceptorConfig
+
+	err = yaml.Unmarshal(b, &ic)
 	if err != nil {
-		config.interceptors = DefaultInterceptorConfig
+		ic = DefaultInterceptorConfig
 	}
 
+	config.SetInterceptors(ic)
+
 	return err
 }
 
+// InterceptorsFilename returns the file of interceptors for the current mode.
+// It returns empty string if there is no file found.
+func (config *AppConfig) InterceptorsFilename() string {
+	filename := FindInterceptorConfigFile(config.RunMode().String(), config.interceptorsRoot())
+	if strings.HasPrefix(filename, GogoSchema) {
+		return ""
+	}
+
+	return filename
+}
+
+func (config *AppConfig) interceptorsRoot() string {
+	return strings.TrimSuffix(path.Dir(config.Filename()), "/config")
+}
+
 // SectionConfig defines config spec for internal usage
 type SectionConfig struct {
 	Server *ServerConfig `yaml:"server"`
@@ -205,18 +244,264 @@ type ServerConfig struct {
 	Port           int    `yaml:"port"`             // listen port
 	RTimeout       int    `yaml:"request_timeout"`  // unit in second
 	WTimeout       int    `yaml:"response_timeout"` // unit in second
+	ITimeout       int    `yaml:"idle_timeout"`     // unit in second, timeout of waiting for the next request with keep-alives
 	MaxHeaderBytes int    `yaml:"max_header_bytes"` // unit in byte
+	MaxConns       int    `yaml:"max_conns"`        // max concurrent connections of per listener, it stops accepting if reached
+	MaxConnsPerIP  int    `yaml:"max_conns_per_ip"` // max concurrent connections of per remote IP, it closes exceeded immediately
 	RequestID      string `yaml:"request_id"`
 
+	// deadline of handlers by request context, routes and groups can overwrite it.
+	// It responds as soon as exceeded if handler has written nothing.
+	HTimeout       int    `yaml:"handler_timeout"`        // unit in second, disabled if zero
+	HTimeoutStatus int    `yaml:"handler_timeout_status"` // valid values [503|504], default to 503
+	HTimeoutBody   string `yaml:"handler_timeout_body"`   // default to text of status
+
 	// ssl support
-	Ssl     bool   `yaml:"ssl"`
-	SslCert string `yaml:"ssl_cert"`
-	SslKey  string `yaml:"ssl_key"`
+	Ssl         bool             `yaml:"ssl"`
+	SslCert     string           `yaml:"ssl_cert"`
+	SslKey      string           `yaml:"ssl_key"`
+	SslCerts    []*SslCertConfig `yaml:"ssl_certs"`    // selected by SNI
+	SslInterval int              `yaml:"ssl_interval"` // interval of checking cert files changed, unit in second
+
+	// mutual tls support
+	SslClientCA   string `yaml:"ssl_client_ca"`   // pem encoded ca bundle for verifying client certificates, required by require and verify_if_given
+	SslClientAuth string `yaml:"ssl_client_auth"` // valid values [none|request|require|verify_if_given], certificates of request are not verified and no client identity resolved
+
+	// PROXY protocol v1/v2 support
+	ProxyProtocol bool     `yaml:"proxy_protocol"`
+	ProxyTrusted  []string `yaml:"proxy_trusted"` // CIDRs of trusted proxies, required by proxy_protocol
 
 	HTTP2    bool `yaml:"http2"`    // enable http2
-	Healthz  bool `yaml:"healthz"`  // enable /-/healthz
+	H2C      bool `yaml:"h2c"`      // enable http2 over plaintext for non-ssl listeners
+	Healthz  bool `yaml:"healthz"`  // enable /-/healthz and /-/readyz
+	Routes   bool `yaml:"routes"`   // enable /-/routes
 	Throttle int  `yaml:"throttle"` // in time.Second/throttle ms
 	Demotion int  `yaml:"demotion"` // concurrency
+
+	// pprof, expvar and runtime stats under /-/debug/
+	Debug *DebugConfig `yaml:"debug"`
+
+	// admin endpoints under /-/admin/, disabled by default
+	Admin *AdminConfig `yaml:"admin"`
+
+	// answer OPTIONS and 405 with Allow header automatically, preflight requests
+	// are answered with cors policy if given
+	AutoOptions bool        `yaml:"auto_options"`
+	CORS        *CORSConfig `yaml:"cors"`
+
+	// interval of checking interceptors file changed, unit in second, default to 10s,
+	// use negative value to disable watching.
+	InterceptorsInterval int `yaml:"interceptors_interval"`
+
+	// graceful shutdown
+	ShutdownSignals []string `yaml:"shutdown_signals"` // e.g. [SIGINT, SIGTERM]
+	ShutdownTimeout int      `yaml:"shutdown_timeout"` // unit in second
+
+	// timeout of each lifecycle phase for all components, unit in second
+	ComponentTimeout int `yaml:"component_timeout"`
+
+	// zero-downtime upgrade by re-exec binary, disabled by default
+	UpgradeSignal  string `yaml:"upgrade_signal"`  // e.g. SIGUSR2
+	UpgradeTimeout int    `yaml:"upgrade_timeout"` // unit in second
+
+	// serve on multiple listeners at once, it overwrites listener settings above
+	Listeners []*ListenerConfig `yaml:"listeners"`
+}
+
+// ListenerConfigs returns all listeners of server. It returns a listener
+// resolved from settings of server if there is no listeners defined.
+//
+// NOTE: Zero values of listener are inherited from server.
+func (c *ServerConfig) ListenerConfigs() []*ListenerConfig {
+	if len(c.Listeners) == 0 {
+		return []*ListenerConfig{
+			{
+				Addr:           c.Addr,
+				Port:           c.Port,
+				RTimeout:       c.RTimeout,
+				WTimeout:       c.WTimeout,
+				ITimeout:       c.ITimeout,
+				MaxHeaderBytes: c.MaxHeaderBytes,
+				MaxConns:       c.MaxConns,
+				MaxConnsPerIP:  c.MaxConnsPerIP,
+				Ssl:            c.Ssl,
+				SslCert:        c.SslCert,
+				SslKey:         c.SslKey,
+				SslCerts:       c.SslCerts,
+				SslClientCA:    c.SslClientCA,
+				SslClientAuth:  c.SslClientAuth,
+				HTTP2:          c.HTTP2,
+				H2C:            c.H2C,
+				ProxyProtocol:  c.ProxyProtocol,
+				ProxyTrusted:   c.ProxyTrusted,
+			},
+		}
+	}
+
+	configs := make([]*ListenerConfig, len(c.Listeners))
+	for i, listener := range c.Listeners {
+		config := *listener
+
+		if config.RTimeout == 0 {
+			config.RTimeout = c.RTimeout
+		}
+		if config.WTimeout == 0 {
+			config.WTimeout = c.WTimeout
+		}
+		if config.ITimeout == 0 {
+			config.ITimeout = c.ITimeout
+		}
+		if config.MaxHeaderBytes == 0 {
+			config.MaxHeaderBytes = c.MaxHeaderBytes
+		}
+		if config.MaxConns == 0 {
+			config.MaxConns = c.MaxConns
+		}
+		if config.MaxConnsPerIP == 0 {
+			config.MaxConnsPerIP = c.MaxConnsPerIP
+		}
+		if config.Ssl && config.SslCert == "" && len(config.SslCerts) == 0 {
+			config.SslCert = c.SslCert
+			config.SslKey = c.SslKey
+			config.SslCerts = c.SslCerts
+		}
+		if config.Ssl && config.SslClientAuth == "" {
+			config.SslClientAuth = c.SslClientAuth
+		}
+		if config.Ssl && config.SslClientCA == "" && config.SslClientAuth != "none" {
+			config.SslClientCA = c.SslClientCA
+		}
+		if config.ProxyProtocol && len(config.ProxyTrusted) == 0 {
+			config.ProxyTrusted = c.ProxyTrusted
+		}
+
+		configs[i] = &config
+	}
+
+	return configs
+}
+
+// ListenerConfig defines config spec of listener for AppServer
+type ListenerConfig struct {
+	Addr           string `yaml:"addr"`             // listen address, e.g. localhost, unix:/tmp/gogo.sock
+	Port           int    `yaml:"port"`             // listen port
+	RTimeout       int    `yaml:"request_timeout"`  // unit in second
+	WTimeout       int    `yaml:"response_timeout"` // unit in second
+	ITimeout       int    `yaml:"idle_timeout"`     // unit in second
+	MaxHeaderBytes int    `yaml:"max_header_bytes"` // unit in byte
+	MaxConns       int    `yaml:"max_conns"`
+	MaxConnsPerIP  int    `yaml:"max_conns_per_ip"`
+
+	// ssl support
+	Ssl      bool             `yaml:"ssl"`
+	SslCert  string           `yaml:"ssl_cert"`
+	SslKey   string           `yaml:"ssl_key"`
+	SslCerts []*SslCertConfig `yaml:"ssl_certs"` // selected by SNI
+
+	// mutual tls support
+	SslClientCA   string `yaml:"ssl_client_ca"`
+	SslClientAuth string `yaml:"ssl_client_auth"` // valid values [none|request|require|verify_if_given], none opts out of server settings
+
+	HTTP2 bool `yaml:"http2"` // enable http2
+	H2C   bool `yaml:"h2c"`   // enable http2 over plaintext, both prior knowledge and Upgrade: h2c are supported
+
+	// PROXY protocol v1/v2 support
+	ProxyProtocol bool     `yaml:"proxy_protocol"`
+	ProxyTrusted  []string `yaml:"proxy_trusted"` // CIDRs of trusted proxies, required by proxy_protocol
+}
+
+// KeyPairs returns all certificates of the listener, the ssl_cert and ssl_key
+// pair always comes first if defined.
+func (c *ListenerConfig) KeyPairs() []listeners.KeyPair {
+	var pairs []listeners.KeyPair
+
+	if c.SslCert != "" || c.SslKey != "" {
+		pairs = append(pairs, listeners.KeyPair{
+			CertFile: c.SslCert,
+			KeyFile:  c.SslKey,
+		})
+	}
+
+	for _, cert := range c.SslCerts {
+		pairs = append(pairs, listeners.KeyPair{
+			CertFile: cert.Cert,
+			KeyFile:  cert.Key,
+		})
+	}
+
+	return pairs
+}
+
+// DebugConfig defines config spec of debug endpoints
+type DebugConfig struct {
+	Enable  *bool    `yaml:"enable"`  // it's enabled in development mode only if omitted
+	Token   string   `yaml:"token"`   // token required by X-Gogo-Token header or Authorization: Bearer
+	Allowed []string `yaml:"allowed"` // IPs or CIDRs of clients allowed
+}
+
+// IsEnabled returns true if debug endpoints should be registered for the mode.
+func (c *DebugConfig) IsEnabled(mode RunMode) bool {
+	if c == nil || c.Enable == nil {
+		return mode.IsDevelopment()
+	}
+
+	return *c.Enable
+}
+
+// AdminConfig defines config spec of admin endpoints
+type AdminConfig struct {
+	Enable  bool     `yaml:"enable"`
+	Token   string   `yaml:"token"`   // token required by X-Gogo-Token header or Authorization: Bearer
+	Allowed []string `yaml:"allowed"` // IPs or CIDRs of clients allowed, loopback only if both of token and allowed are empty
+}
+
+// IsEnabled returns true if admin endpoints should be registered.
+func (c *AdminConfig) IsEnabled() bool {
+	return c != nil && c.Enable
+}
+
+// CORSConfig defines config spec of CORS policy
+type CORSConfig struct {
+	AllowedOrigins   []string `yaml:"allowed_origins"`   // * for any origin
+	AllowedMethods   []string `yaml:"allowed_methods"`   // default to methods registered of the path
+	AllowedHeaders   []string `yaml:"allowed_headers"`   // default to headers requested
+	ExposedHeaders   []string `yaml:"exposed_headers"`   // response headers readable by clients
+	AllowCredentials bool     `yaml:"allow_credentials"` // requires explicit allowed_origins, * is rejected
+	MaxAge           int      `yaml:"max_age"`           // unit in second, cache time of preflight result
+}
+
+// SslCertConfig defines config spec of certificate/key pair
+type SslCertConfig struct {
+	Cert string `yaml:"cert"`
+	Key  string `yaml:"key"`
+}
+
+// Resolve returns network and address of the listener.
+//
+// If the port is zero, treat the address as a fully qualified local address.
+// This address must be prefixed with the network type followed by a colon,
+// e.g. unix:/tmp/gogo.socket or tcp6:::1 (equivalent to tcp6:0:0:0:0:0:0:0:1)
+func (c *ListenerConfig) Resolve() (network, addr string) {
+	network = "tcp"
+	addr = c.Addr
+
+	matches := network2addr.FindStringSubmatch(addr)
+	if len(matches) == 3 {
+		switch strings.ToLower(matches[1]) {
+		case "http", "https":
+			// ignore
+		default:
+			network = matches[1]
+		}
+
+		addr = "/" + strings.TrimPrefix(matches[2], "/")
+	}
+
+	if c.Port != 0 {
+		addr += ":" + strconv.Itoa(c.Port)
+	}
+
+	return
 }
 
 // InterceptorConfig defines config spec of middleware
@@ -42,6 +42,7 @@ func Test_NewConfig(t *testing.T) {
 	config, err := fakeConfig("application.yml")
 	if it.Nil(err) {
 		it.Implements((*Configer)(nil), config)
+		it.Implements((*ReloadableConfiger)(nil), config)
 
 		it.Equal(Test, config.Mode)
 		it.Equal("gogo", config.Name)
@@ -133,3 +134,47 @@ func Test_ConfigWithDefaults(t *testing.T) {
 		}
 	}
 }
+
+func Test_ServerConfigListenerConfigs(t *testing.T) {
+	it := assert.New(t)
+
+	config, _ := fakeConfig("application.listeners.yml")
+
+	listeners := config.Section().Server.ListenerConfigs()
+	if it.Len(listeners, 2) {
+		it.Equal(3, listeners[0].RTimeout)
+		it.Equal(10, listeners[0].WTimeout)
+
+		network, addr := listeners[1].Resolve()
+		it.Equal("unix", network)
+		it.Equal("/tmp/gogo.listeners.sock", addr)
+		it.Equal(1, listeners[1].RTimeout)
+		it.Equal(10, listeners[1].WTimeout)
+	}
+
+	// it should resolve from server without listeners
+	config, _ = fakeConfig("application.unix.yml")
+
+	listeners = config.Section().Server.ListenerConfigs()
+	if it.Len(listeners, 1) {
+		network, addr := listeners[0].Resolve()
+		it.Equal("unix", network)
+		it.Equal("/tmp/gogo.sock", addr)
+	}
+
+	// it should opt out of mutual tls of server with none
+	config, _ = fakeConfig("application.mtls.listeners.yml")
+
+	listeners = config.Section().Server.ListenerConfigs()
+	if it.Len(listeners, 3) {
+		it.Equal("verify_if_given", listeners[0].SslClientAuth)
+		it.Equal("testdata/ssl/ca.crt", listeners[0].SslClientCA)
+
+		it.Equal("none", listeners[1].SslClientAuth)
+		it.Empty(listeners[1].SslClientCA)
+
+		// it should inherit client auth only with its own client ca
+		it.Equal("verify_if_given", listeners[2].SslClientAuth)
+		it.Equal("testdata/ssl/internal.ca.crt", listeners[2].SslClientCA)
+	}
+}
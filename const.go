@@ -10,17 +10,34 @@ const (
 // gogo schema and internal route
 const (
 	GogoSchema  = "gogo://"
+	GogoPrefix  = "/-/" // prefix of internal routes
 	GogoHealthz = "/-/healthz"
+	GogoReadyz  = "/-/readyz"
+	GogoRoutes  = "/-/routes"
+	GogoDebug   = "/-/debug/"
+	GogoAdmin   = "/-/admin/"
 )
 
 // server defaults
 const (
-	DefaultRequestIDKey    = "X-Request-Id"
-	DefaultRequestIDMaxLen = 32
-	DefaultRequestTimeout  = 10 // 10s
-	DefaultResponseTimeout = 10 // 10s
+	DefaultRequestIDKey         = "X-Request-Id"
+	DefaultRequestIDMaxLen      = 32
+	DefaultAdminTokenHeader     = "X-Gogo-Token"
+	DefaultRequestTimeout       = 10 // 10s
+	DefaultResponseTimeout      = 10 // 10s
+	DefaultShutdownTimeout      = 30 // 30s
+	DefaultUpgradeTimeout       = 30 // 30s
+	DefaultComponentTimeout     = 10 // 10s
+	DefaultHealthTimeout        = 3  // 3s
+	DefaultDebugGrace           = 5  // 5s
+	DefaultSslInterval          = 10 // 10s
+	DefaultInterceptorsInterval = 10 // 10s
+	DefaultReloadSignal         = "SIGHUP"
 )
 
+// DefaultShutdownSignals defines signals for graceful shutdown of AppServer.Serve
+var DefaultShutdownSignals = []string{"SIGINT", "SIGTERM"}
+
 // RunMode defines app run mode
 type RunMode string
 
@@ -57,4 +74,6 @@ type contextKey int
 
 const (
 	ctxLoggerKey contextKey = iota + 1
+	ctxRouteKey
+	ctxVersionKey
 )
@@ -0,0 +1,151 @@
+package gogo
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dolab/httpdispatch"
+)
+
+// paramTypes defines builtin constraints of path params, values matched can be
+// retrieved by converters of params without error, e.g. ctx.Params.GetInt64.
+var paramTypes = map[string]func(value string) bool{
+	"int":    parseIntFunc(strconv.IntSize),
+	"int8":   parseIntFunc(8),
+	"int16":  parseIntFunc(16),
+	"int32":  parseIntFunc(32),
+	"int64":  parseIntFunc(64),
+	"uint":   parseUintFunc(strconv.IntSize),
+	"uint8":  parseUintFunc(8),
+	"uint16": parseUintFunc(16),
+	"uint32": parseUintFunc(32),
+	"uint64": parseUintFunc(64),
+	"float": func(value string) bool {
+		_, err := strconv.ParseFloat(value, 64)
+		return err == nil
+	},
+	"bool": func(value string) bool {
+		_, err := strconv.ParseBool(value)
+		return err == nil
+	},
+	"uuid": regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`).MatchString,
+}
+
+func parseIntFunc(bitSize int) func(string) bool {
+	return func(value string) bool {
+		_, err := strconv.ParseInt(value, 10, bitSize)
+		return err == nil
+	}
+}
+
+func parseUintFunc(bitSize int) func(string) bool {
+	return func(value string) bool {
+		_, err := strconv.ParseUint(value, 10, bitSize)
+		return err == nil
+	}
+}
+
+// A paramConstraint defines constraint of a path param
+type paramConstraint struct {
+	name  string
+	match func(value string) bool
+}
+
+// newParamConstraint returns *paramConstraint of builtin type or regular
+// expression which must match the whole value.
+func newParamConstraint(name, expr string) *paramConstraint {
+	if expr == "" {
+		panic(ErrRouteConstraint)
+	}
+
+	match, ok := paramTypes[expr]
+	if !ok {
+		match = regexp.MustCompile("^(?:" + expr + ")$").MatchString
+	}
+
+	return &paramConstraint{
+		name:  name,
+		match: match,
+	}
+}
+
+// paramConstraints defines all constraints of path params of a route
+type paramConstraints []*paramConstraint
+
+// parseConstraints returns path without constraints and constraints of params,
+// e.g. /users/:id for /users/:id<int>. A constraint is a builtin type, such as
+// int, uint, float, bool and uuid, or a regular expression, e.g. :name<[a-z]+>.
+func parseConstraints(uri string) (string, paramConstraints) {
+	if !strings.Contains(uri, "<") {
+		return uri, nil
+	}
+
+	var (
+		buf         strings.Builder
+		constraints paramConstraints
+	)
+
+	for i := 0; i < len(uri); {
+		if uri[i] != ':' && uri[i] != '*' {
+			buf.WriteByte(uri[i])
+			i++
+			continue
+		}
+
+		// name of param
+		j := i + 1
+		for j < len(uri) && uri[j] != '/' && uri[j] != '<' {
+			j++
+		}
+
+		buf.WriteString(uri[i:j])
+
+		if j == len(uri) || uri[j] != '<' {
+			i = j
+			continue
+		}
+
+		// constraint ends with > followed by / or end of path
+		k := j + 1
+		for k < len(uri) && !(uri[k] == '>' && (k+1 == len(uri) || uri[k+1] == '/')) {
+			k++
+		}
+		if k == len(uri) {
+			panic(ErrRouteConstraint)
+		}
+
+		constraints = append(constraints, newParamConstraint(uri[i+1:j], uri[j+1:k]))
+
+		i = k + 1
+	}
+
+	return buf.String(), constraints
+}
+
+// match returns true if all params satisfy their constraints
+func (constraints paramConstraints) match(ps httpdispatch.Params) bool {
+	for _, constraint := range constraints {
+		if !constraint.match(ps.ByName(constraint.name)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// serveNotFound responds with NotFound handler of routes tree
+func (r *AppGroup) serveNotFound(w http.ResponseWriter, req *http.Request) {
+	if router, ok := r.handler.(*versionRouter); ok {
+		router.group.group.serveNotFound(w, req)
+		return
+	}
+
+	if dispatcher, ok := r.handler.(*httpdispatch.Dispatcher); ok && dispatcher.NotFound != nil {
+		dispatcher.NotFound.ServeHTTP(w, req)
+		return
+	}
+
+	http.NotFound(w, req)
+}
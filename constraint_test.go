@@ -0,0 +1,111 @@
+package gogo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golib/assert"
+)
+
+func Test_ParseConstraints(t *testing.T) {
+	it := assert.New(t)
+
+	uri, constraints := parseConstraints("/users/:id")
+	it.Equal("/users/:id", uri)
+	it.Nil(constraints)
+
+	uri, constraints = parseConstraints("/users/:id<int>/files/:name<[a-z0-9-]+>/*path<.+\\.(css|js)>")
+	it.Equal("/users/:id/files/:name/*path", uri)
+	if it.Len(constraints, 3) {
+		it.Equal("id", constraints[0].name)
+		it.True(constraints[0].match("-1"))
+		it.False(constraints[0].match("1.0"))
+		it.False(constraints[0].match("99999999999999999999"))
+
+		it.Equal("name", constraints[1].name)
+		it.True(constraints[1].match("a-1"))
+		it.False(constraints[1].match("a_1"))
+
+		it.Equal("path", constraints[2].name)
+		it.True(constraints[2].match("/assets/app.css"))
+		it.False(constraints[2].match("/assets/app.html"))
+	}
+
+	for _, uri := range []string{"/users/:id<>", "/users/:id<int", "/users/:id<[a-z>"} {
+		it.Panics(func() {
+			parseConstraints(uri)
+		}, uri)
+	}
+}
+
+func Test_GroupWithConstraints(t *testing.T) {
+	it := assert.New(t)
+	server := fakeServer()
+
+	var filtered int
+	group := server.NewGroup("/v1", func(ctx *Context) {
+		filtered++
+
+		ctx.Next()
+	})
+	group.GET("/users/:id<uint>", func(ctx *Context) {
+		id, err := ctx.Params.GetUint64("id")
+		it.Nil(err)
+
+		ctx.Text(ctx.Params.Get("id"))
+		it.NotZero(id)
+	}, RouteName("users.show"))
+	group.GET("/files/:name<[a-z0-9-]+>", func(ctx *Context) {
+		ctx.Text(ctx.Params.Get("name"))
+	})
+
+	testCases := map[string]int{
+		"/v1/users/1":         http.StatusOK,
+		"/v1/users/-1":        http.StatusNotFound,
+		"/v1/users/abc":       http.StatusNotFound,
+		"/v1/files/gogo-v1":   http.StatusOK,
+		"/v1/files/gogo_v1":   http.StatusNotFound,
+		"/v1/files/GOGO-V1":   http.StatusNotFound,
+		"/v1/files/gogo-v1.0": http.StatusNotFound,
+	}
+	for uri, status := range testCases {
+		filtered = 0
+
+		r, _ := http.NewRequest(http.MethodGet, uri, nil)
+		w := httptest.NewRecorder()
+
+		server.ServeHTTP(w, r)
+		it.Equal(status, w.Code, uri)
+
+		if status == http.StatusOK {
+			it.Equal(1, filtered, uri)
+		} else {
+			it.Zero(filtered, uri)
+		}
+	}
+
+	// it should work with named routes
+	uri, err := server.URLFor("users.show", 1)
+	if it.Nil(err) {
+		it.Equal("/v1/users/1", uri)
+	}
+}
+
+func Test_GroupWithConstraintsAndNotFound(t *testing.T) {
+	it := assert.New(t)
+	server := fakeServer()
+
+	server.GET("/users/:id<int>", fakePackageAction)
+	server.SetNotFound(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("custom not found"))
+	}))
+
+	r, _ := http.NewRequest(http.MethodGet, "/users/abc", nil)
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusNotFound, w.Code)
+	it.Equal("custom not found", w.Body.String())
+}
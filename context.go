@@ -12,6 +12,8 @@ import (
 	"github.com/dolab/gogo/internal/params"
 	"github.com/dolab/gogo/internal/render"
 	"github.com/dolab/gogo/pkgs/hooks"
+	"github.com/dolab/gogo/pkgs/identity"
+	"github.com/dolab/gogo/pkgs/proxyproto"
 )
 
 var (
@@ -60,6 +62,8 @@ type Context struct {
 	filters       []Middleware
 	responseReady *hooks.HookList
 	issuedAt      time.Time
+	names         *routeNames
+	route         *Route
 }
 
 // NewContext returns a *Context without initialization
@@ -85,6 +89,34 @@ func (c *Context) Action() string {
 	return c.action
 }
 
+// Route returns metadata of route which request routed to, it returns nil if
+// the context is not created by routing.
+//
+// NOTE: The returned *Route is shared by all requests of the route, DO NOT modify it.
+func (c *Context) Route() *Route {
+	return c.route
+}
+
+// Version returns API version which request dispatched by VersionedGroup, it
+// returns empty for routes without version.
+func (c *Context) Version() string {
+	return VersionFromRequest(c.Request)
+}
+
+// ClientIdentity returns identity of verified client certificate for mutual TLS,
+// it returns nil if there is no verified client certificate.
+//
+// NOTE: It always returns nil for ssl_client_auth of request, which never verifies certificates.
+func (c *Context) ClientIdentity() *identity.Identity {
+	return identity.FromRequest(c.Request)
+}
+
+// ProxyHeader returns PROXY header of the connection which request received from,
+// it returns nil if PROXY protocol is disabled or there is no header sent.
+func (c *Context) ProxyHeader() *proxyproto.Header {
+	return proxyproto.FromRequest(c.Request)
+}
+
 // ContentType returns first value of request Content-Type header separated by semicolon
 func (c *Context) ContentType() string {
 	s := c.Header("Content-Type")
@@ -237,6 +269,18 @@ func (c *Context) SetHeader(key, value string) {
 	c.Response.Header().Set(key, value)
 }
 
+// URLFor returns path of route named with params in order, it's useful for
+// Redirect and Location header. Routes of the same host as the current route
+// are resolved first. See AppServer.URLFor for details.
+func (c *Context) URLFor(name string, params ...interface{}) (string, error) {
+	var host string
+	if c.route != nil {
+		host = c.route.Host
+	}
+
+	return c.names.urlFor(host, name, params...)
+}
+
 // Redirect returns a HTTP redirect to the specific location.
 func (c *Context) Redirect(location string) {
 	// always abort
@@ -7,6 +7,7 @@ import (
 	"math"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -562,6 +563,10 @@ func Test_contextNew(t *testing.T) {
 	request = request.WithContext(context.WithValue(request.Context(), ctxLoggerKey, NewAppLogger("nil", "")))
 	params := params.NewParams(request, httpdispatch.Params{})
 
+	// states are reset by Context.run, drop contexts reused by other tests from pool
+	runtime.GC()
+	runtime.GC()
+
 	ctx := contextNew(recorder, request, params, "package", "controller", "action")
 	it.Equal(recorder, ctx.Response.(*Response).ResponseWriter)
 	it.Equal(request, ctx.Request)
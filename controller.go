@@ -45,3 +45,32 @@ type ControllerExplore interface {
 type ControllerDestroy interface {
 	Destroy(c *Context)
 }
+
+// A ControllerAction defines a custom action of Resource. Path is relative to the
+// resource for collection actions, and relative to the member for member actions,
+// e.g. cancel for POST /orders/:order/cancel. Name is used for named routes, it's
+// resolved from Path if empty, e.g. orders.search for collection actions and
+// orders.member.cancel for member actions.
+type ControllerAction struct {
+	Method  string
+	Path    string
+	Handler Middleware
+	Name    string
+}
+
+// ControllerMemberActions is an interface that wraps the MemberActions method. It is used by
+// Resource for registering custom routes of /resource/:id, e.g. POST /orders/:order/cancel.
+type ControllerMemberActions interface {
+	MemberActions() []ControllerAction
+}
+
+// ControllerCollectionActions is an interface that wraps the CollectionActions method. It is used by
+// Resource for registering custom routes of /resource, e.g. POST /orders/search.
+//
+// NOTE: Paths must not conflict with routes of /resource/:id with the same method, e.g.
+// GET /orders/search conflicts with GET /orders/:order registered for Show, and POST
+// /orders/search conflicts with POST /orders/:order/cancel of member actions. Resource
+// panics with ErrActionConflicted for conflicts.
+type ControllerCollectionActions interface {
+	CollectionActions() []ControllerAction
+}
@@ -0,0 +1,206 @@
+package gogo
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// handlerDeadline defines default timeout of handlers and response for deadline exceeded
+type handlerDeadline struct {
+	timeout time.Duration
+	status  int
+	body    string
+}
+
+// newHandlerDeadline returns handlerDeadline of server config, the status is
+// adjusted to 503 if it's neither 503 nor 504.
+func newHandlerDeadline(config *ServerConfig) handlerDeadline {
+	deadline := handlerDeadline{
+		timeout: time.Duration(config.HTimeout) * time.Second,
+		status:  config.HTimeoutStatus,
+		body:    config.HTimeoutBody,
+	}
+
+	switch deadline.status {
+	case http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		// ignore
+
+	default:
+		deadline.status = http.StatusServiceUnavailable
+	}
+
+	if deadline.body == "" {
+		deadline.body = http.StatusText(deadline.status)
+	}
+
+	return deadline
+}
+
+// respond writes status and body of deadline to w
+func (deadline *handlerDeadline) respond(w http.ResponseWriter) {
+	// server is not prepared, e.g. testing with httptest.Server
+	status, body := http.StatusServiceUnavailable, http.StatusText(http.StatusServiceUnavailable)
+	if deadline != nil && deadline.status != 0 {
+		status, body = deadline.status, deadline.body
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write([]byte(body))
+}
+
+// deadlineWriter guards writes of handler against responding for deadline exceeded,
+// it works like the writer of http.TimeoutHandler except that only headers are
+// kept aside until written, so handlers can still stream and flush.
+type deadlineWriter struct {
+	mux      sync.Mutex
+	w        http.ResponseWriter
+	r        *http.Request
+	deadline *handlerDeadline
+	header   http.Header
+	wrote    bool // headers have been written by handler
+	timedout bool // deadline has responded, all writes of handler are discarded
+	finished bool // handler has returned, deadline can not respond any more
+}
+
+func newDeadlineWriter(w http.ResponseWriter, r *http.Request, deadline *handlerDeadline) *deadlineWriter {
+	return &deadlineWriter{
+		w:        w,
+		r:        r,
+		deadline: deadline,
+		header:   make(http.Header),
+	}
+}
+
+// Header implements http.ResponseWriter interface
+func (dw *deadlineWriter) Header() http.Header {
+	dw.mux.Lock()
+	defer dw.mux.Unlock()
+
+	if dw.wrote {
+		return dw.w.Header()
+	}
+
+	return dw.header
+}
+
+// WriteHeader implements http.ResponseWriter interface
+func (dw *deadlineWriter) WriteHeader(code int) {
+	dw.mux.Lock()
+	defer dw.mux.Unlock()
+
+	dw.writeHeader(code)
+}
+
+// Write implements http.ResponseWriter interface, it returns http.ErrHandlerTimeout
+// if deadline has responded.
+func (dw *deadlineWriter) Write(data []byte) (int, error) {
+	dw.mux.Lock()
+	defer dw.mux.Unlock()
+
+	if dw.expired() {
+		return 0, http.ErrHandlerTimeout
+	}
+
+	dw.writeHeader(http.StatusOK)
+
+	return dw.w.Write(data)
+}
+
+// Flush implements http.Flusher interface
+func (dw *deadlineWriter) Flush() {
+	dw.mux.Lock()
+	defer dw.mux.Unlock()
+
+	if dw.expired() {
+		return
+	}
+
+	dw.writeHeader(http.StatusOK)
+
+	if flusher, ok := dw.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker interface, deadline can not respond after hijacked.
+func (dw *deadlineWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	dw.mux.Lock()
+	defer dw.mux.Unlock()
+
+	if dw.expired() {
+		return nil, nil, http.ErrHandlerTimeout
+	}
+
+	hijacker, ok := dw.w.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+
+	conn, rw, err := hijacker.Hijack()
+	if err == nil {
+		dw.wrote = true
+	}
+
+	return conn, rw, err
+}
+
+// Unwrap returns the underlying http.ResponseWriter for http.ResponseController
+func (dw *deadlineWriter) Unwrap() http.ResponseWriter {
+	return dw.w
+}
+
+// expire responds for deadline exceeded if handler has written nothing, it's
+// called as soon as deadline exceeded.
+func (dw *deadlineWriter) expire() {
+	dw.mux.Lock()
+	dw.expired()
+	dw.mux.Unlock()
+}
+
+// finish marks handler returned, it waits for responding of deadline in progress.
+func (dw *deadlineWriter) finish() {
+	dw.mux.Lock()
+	dw.expired()
+	dw.finished = true
+	dw.mux.Unlock()
+}
+
+// expired responds status and body of deadline if request context exceeded
+// without anything written, and returns true if responded.
+func (dw *deadlineWriter) expired() bool {
+	if dw.timedout {
+		return true
+	}
+
+	if dw.wrote || dw.finished || dw.r.Context().Err() != context.DeadlineExceeded {
+		return false
+	}
+
+	dw.timedout = true
+
+	NewRequestLogger(dw.r).Warnf("Request(%s %s): handler timeout", dw.r.Method, dw.r.URL.RequestURI())
+
+	dw.deadline.respond(dw.w)
+
+	return true
+}
+
+func (dw *deadlineWriter) writeHeader(code int) {
+	if dw.wrote || dw.expired() {
+		return
+	}
+
+	dw.wrote = true
+
+	header := dw.w.Header()
+	for key, values := range dw.header {
+		header[key] = values
+	}
+
+	dw.w.WriteHeader(code)
+}
@@ -0,0 +1,201 @@
+package gogo
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golib/assert"
+)
+
+type deadlineRecorder struct {
+	*httptest.ResponseRecorder
+
+	written chan struct{}
+}
+
+func (w *deadlineRecorder) WriteHeader(code int) {
+	w.ResponseRecorder.WriteHeader(code)
+
+	close(w.written)
+}
+
+func Test_newHandlerDeadline(t *testing.T) {
+	it := assert.New(t)
+
+	deadline := newHandlerDeadline(&ServerConfig{
+		HTimeout:       3,
+		HTimeoutStatus: http.StatusInternalServerError,
+	})
+	it.Equal(3*time.Second, deadline.timeout)
+	it.Equal(http.StatusServiceUnavailable, deadline.status)
+	it.Equal(http.StatusText(http.StatusServiceUnavailable), deadline.body)
+
+	deadline = newHandlerDeadline(&ServerConfig{
+		HTimeoutStatus: http.StatusGatewayTimeout,
+		HTimeoutBody:   "timeout",
+	})
+	it.Zero(deadline.timeout)
+	it.Equal(http.StatusGatewayTimeout, deadline.status)
+	it.Equal("timeout", deadline.body)
+}
+
+func Test_ServerWithDeadline(t *testing.T) {
+	it := assert.New(t)
+	logger := NewAppLogger("nil", "")
+	config, _ := fakeConfig("application.deadline.yml")
+
+	server := NewAppServer(config, logger)
+
+	blocked := make(chan struct{})
+
+	slow := func(ctx *Context) {
+		<-ctx.Request.Context().Done()
+	}
+
+	group := server.NewGroup("/deadline")
+	group.SetTimeout(20 * time.Millisecond)
+	group.GET("/slow", slow)
+	group.GET("/fast", func(ctx *Context) {
+		ctx.Text("fast")
+	})
+	group.GET("/flushed", func(ctx *Context) {
+		ctx.Response.FlushHeader()
+
+		<-ctx.Request.Context().Done()
+
+		ctx.Text("flushed")
+	})
+	group.GET("/blocked", func(ctx *Context) {
+		<-ctx.Request.Context().Done()
+
+		// wait for responding of deadline
+		time.Sleep(10 * time.Millisecond)
+
+		_, err := ctx.Response.Write([]byte("blocked"))
+		if err != http.ErrHandlerTimeout {
+			panic(err)
+		}
+
+		<-blocked
+	})
+	group.GET("/disabled", func(ctx *Context) {
+		if _, ok := ctx.Request.Context().Deadline(); ok {
+			ctx.SetStatus(http.StatusInternalServerError)
+		}
+
+		ctx.Text("disabled")
+	}, RouteTimeout(0))
+
+	// it should inherit timeout of parent group
+	group.NewGroup("/nested").GET("/slow", slow)
+
+	// it should overwrite timeout of group
+	server.GET("/route/slow", slow, RouteTimeout(20*time.Millisecond))
+
+	server.prepare()
+
+	request := func(uri string) *httptest.ResponseRecorder {
+		r, _ := http.NewRequest(http.MethodGet, uri, nil)
+		w := httptest.NewRecorder()
+
+		server.ServeHTTP(w, r)
+
+		return w
+	}
+
+	for _, uri := range []string{"/deadline/slow", "/deadline/nested/slow", "/route/slow"} {
+		w := request(uri)
+		it.Equal(http.StatusGatewayTimeout, w.Code, uri)
+		it.Equal("handler timeout!", w.Body.String(), uri)
+	}
+
+	w := request("/deadline/fast")
+	it.Equal(http.StatusOK, w.Code)
+	it.Equal("fast", w.Body.String())
+
+	// it should not overwrite response flushed
+	w = request("/deadline/flushed")
+	it.Equal(http.StatusOK, w.Code)
+	it.Equal("flushed", w.Body.String())
+
+	// it should respond without waiting for handler returned
+	r, _ := http.NewRequest(http.MethodGet, "/deadline/blocked", nil)
+
+	recorder := &deadlineRecorder{
+		ResponseRecorder: httptest.NewRecorder(),
+		written:          make(chan struct{}),
+	}
+	done := make(chan struct{})
+	go func() {
+		server.ServeHTTP(recorder, r)
+		close(done)
+	}()
+
+	select {
+	case <-recorder.written:
+	case <-time.After(time.Second):
+		t.Fatal("Expected responding for deadline exceeded")
+	}
+
+	select {
+	case <-done:
+		t.Fatal("Expected responding before handler returned")
+
+	default:
+		close(blocked)
+	}
+	<-done
+
+	it.Equal(http.StatusGatewayTimeout, recorder.Code)
+	it.Equal("handler timeout!", recorder.Body.String())
+
+	w = request("/deadline/disabled")
+	it.Equal(http.StatusOK, w.Code)
+	it.Equal("disabled", w.Body.String())
+}
+
+func Test_ServerWithDeadlineHijack(t *testing.T) {
+	it := assert.New(t)
+	logger := NewAppLogger("nil", "")
+	config, _ := fakeConfig("application.deadline.yml")
+
+	server := NewAppServer(config, logger)
+	server.GET("/controller", func(ctx *Context) {
+		err := http.NewResponseController(ctx.Response).SetWriteDeadline(time.Now().Add(time.Second))
+		if err != nil {
+			ctx.SetStatus(http.StatusInternalServerError)
+		}
+
+		ctx.Text("controller")
+	}, RouteTimeout(time.Second))
+	server.GET("/hijack", func(ctx *Context) {
+		conn, rw, err := http.NewResponseController(ctx.Response).Hijack()
+		if err != nil {
+			ctx.SetStatus(http.StatusInternalServerError)
+			return
+		}
+		defer conn.Close()
+
+		rw.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 6\r\nConnection: close\r\n\r\nhijack")
+		rw.Flush()
+	}, RouteTimeout(time.Second))
+
+	server.prepare()
+
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	for _, uri := range []string{"/controller", "/hijack"} {
+		response, err := http.Get(ts.URL + uri)
+		if it.Nil(err, uri) {
+			data, _ := ioutil.ReadAll(response.Body)
+			response.Body.Close()
+
+			it.Equal(http.StatusOK, response.StatusCode, uri)
+			it.Equal(uri[1:], string(data), uri)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package gogo
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"runtime/debug"
+	rpprof "runtime/pprof"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dolab/httpdispatch"
+)
+
+// A GCStats defines stats of garbage collector and heap
+type GCStats struct {
+	NumGC         int64     `json:"num_gc"`
+	LastGC        time.Time `json:"last_gc"`
+	PauseTotal    float64   `json:"pause_total_ms"`
+	Goroutines    int       `json:"goroutines"`
+	HeapAlloc     uint64    `json:"heap_alloc"`
+	HeapSys       uint64    `json:"heap_sys"`
+	HeapObjects   uint64    `json:"heap_objects"`
+	NextGC        uint64    `json:"next_gc"`
+	GCCPUFraction float64   `json:"gc_cpu_fraction"`
+}
+
+// DebugHandle defines a wrapper of handler for /-/debug/, it serves
+//
+//	/-/debug/pprof/       profiles of net/http/pprof
+//	/-/debug/vars         expvar in JSON
+//	/-/debug/goroutines   stack traces of all goroutines in text
+//	/-/debug/gc           stats of GC and heap in JSON
+type DebugHandle struct {
+	handler http.Handler
+}
+
+// NewDebugHandle creates a new handler for debug endpoints protected by token
+// and allowlist of config. It allows loopback clients only if config is nil.
+func NewDebugHandle(config *DebugConfig) (*DebugHandle, error) {
+	if config == nil {
+		config = &DebugConfig{}
+	}
+
+	guard, err := newAdminGuard(config.Token, config.Allowed)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", debugSampling(pprof.Profile, 30*time.Second))
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", debugSampling(pprof.Trace, time.Second))
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.HandleFunc("/debug/goroutines", debugGoroutines)
+	mux.HandleFunc("/debug/gc", debugGC)
+
+	// NOTE: net/http/pprof resolves profile name with hard coded /debug/pprof/ prefix
+	handler := http.StripPrefix(strings.TrimSuffix(GogoPrefix, "/"), mux)
+
+	return &DebugHandle{
+		handler: guard.Wrap(handler),
+	}, nil
+}
+
+// Handle implements httpdispatch.Handler interface
+func (h *DebugHandle) Handle(w http.ResponseWriter, r *http.Request, ps httpdispatch.Params) {
+	w.Header().Set("Cache-Control", "no-cache")
+
+	h.handler.ServeHTTP(w, r)
+}
+
+// debugSampling wraps pprof handlers sampling for ?seconds=N, it extends write
+// deadline of the connection to N plus a grace period, so results of sampling
+// longer than response_timeout of server are not truncated.
+func debugSampling(handler http.HandlerFunc, duration time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		duration := duration
+		if seconds, err := strconv.ParseFloat(r.FormValue("seconds"), 64); err == nil && seconds > 0 {
+			duration = time.Duration(seconds * float64(time.Second))
+		}
+
+		err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(duration + DefaultDebugGrace*time.Second))
+		if err == nil {
+			// NOTE: net/http/pprof of old go rejects seconds exceeding WriteTimeout of
+			// *http.Server found in context, hide it since the deadline is extended.
+			r = r.WithContext(context.WithValue(r.Context(), http.ServerContextKey, nil))
+		}
+
+		handler(w, r)
+	}
+}
+
+func debugGoroutines(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	rpprof.Lookup("goroutine").WriteTo(w, 2)
+}
+
+func debugGC(w http.ResponseWriter, r *http.Request) {
+	var (
+		gc  debug.GCStats
+		mem runtime.MemStats
+	)
+
+	debug.ReadGCStats(&gc)
+	runtime.ReadMemStats(&mem)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	json.NewEncoder(w).Encode(&GCStats{
+		NumGC:         gc.NumGC,
+		LastGC:        gc.LastGC,
+		PauseTotal:    float64(gc.PauseTotal) / float64(time.Millisecond),
+		Goroutines:    runtime.NumGoroutine(),
+		HeapAlloc:     mem.HeapAlloc,
+		HeapSys:       mem.HeapSys,
+		HeapObjects:   mem.HeapObjects,
+		NextGC:        mem.NextGC,
+		GCCPUFraction: mem.GCCPUFraction,
+	})
+}
@@ -0,0 +1,130 @@
+package gogo
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golib/assert"
+)
+
+func Test_DebugConfigIsEnabled(t *testing.T) {
+	it := assert.New(t)
+
+	var config *DebugConfig
+	it.True(config.IsEnabled(Development))
+	it.False(config.IsEnabled(Production))
+
+	enable := false
+	config = &DebugConfig{
+		Enable: &enable,
+	}
+	it.False(config.IsEnabled(Development))
+
+	enable = true
+	it.True(config.IsEnabled(Production))
+}
+
+func Test_ServerWithDebug(t *testing.T) {
+	it := assert.New(t)
+	logger := NewAppLogger("nil", "")
+	config, _ := fakeConfig("application.debug.yml")
+
+	server := NewAppServer(config, logger)
+	server.prepare()
+
+	newRequest := func(uri string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, uri, nil)
+		r.RemoteAddr = "10.0.0.1:8080"
+		r.Header.Set(DefaultAdminTokenHeader, "s3cret")
+
+		return r
+	}
+
+	// pprof
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, newRequest(GogoDebug+"pprof/"))
+	it.Equal(http.StatusOK, w.Code)
+	it.Contains(w.Body.String(), "goroutine")
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, newRequest(GogoDebug+"pprof/heap?debug=1"))
+	it.Equal(http.StatusOK, w.Code)
+	it.Contains(w.Body.String(), "heap profile")
+
+	// expvar
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, newRequest(GogoDebug+"vars"))
+	if it.Equal(http.StatusOK, w.Code) {
+		var vars map[string]interface{}
+		if it.Nil(json.NewDecoder(w.Body).Decode(&vars)) {
+			it.NotNil(vars["memstats"])
+		}
+	}
+
+	// goroutines
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, newRequest(GogoDebug+"goroutines"))
+	it.Equal(http.StatusOK, w.Code)
+	it.True(strings.HasPrefix(w.Body.String(), "goroutine "))
+
+	// gc
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, newRequest(GogoDebug+"gc"))
+	if it.Equal(http.StatusOK, w.Code) {
+		var stats GCStats
+		if it.Nil(json.NewDecoder(w.Body).Decode(&stats)) {
+			it.NotZero(stats.Goroutines)
+			it.NotZero(stats.HeapAlloc)
+		}
+	}
+
+	// it should deny without token
+	r := newRequest(GogoDebug + "gc")
+	r.Header.Del(DefaultAdminTokenHeader)
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusForbidden, w.Code)
+
+	// it should be disabled by default in test mode
+	server = fakeServer()
+	server.prepare()
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, newRequest(GogoDebug+"gc"))
+	it.Equal(http.StatusNotFound, w.Code)
+}
+
+func Test_ServerWithDebugSampling(t *testing.T) {
+	it := assert.New(t)
+	logger := NewAppLogger("nil", "")
+	config, _ := fakeConfig("application.debug.timeout.yml")
+
+	server := NewAppServer(config, logger)
+
+	err := server.Start(context.Background())
+	if !it.Nil(err) {
+		return
+	}
+	defer server.Stop(context.Background())
+
+	// it should sample longer than response_timeout
+	r, _ := http.NewRequest(http.MethodGet, "http://"+server.Address()+GogoDebug+"pprof/trace?seconds=1.5", nil)
+	r.Header.Set(DefaultAdminTokenHeader, "s3cret")
+
+	response, err := http.DefaultClient.Do(r)
+	if it.Nil(err) {
+		defer response.Body.Close()
+
+		data, err := ioutil.ReadAll(response.Body)
+		it.Nil(err)
+		it.Equal(http.StatusOK, response.StatusCode)
+		it.Equal("application/octet-stream", response.Header.Get("Content-Type"))
+		it.NotEmpty(data)
+	}
+}
@@ -2,13 +2,80 @@ package gogo
 
 import (
 	"errors"
+	"fmt"
 )
 
 // errors
 var (
-	ErrConfigSection      = errors.New("Config section does not exist")
-	ErrSettingsKey        = errors.New("Settings key is duplicated")
-	ErrHeaderFlushed      = errors.New("Response headers have been written")
-	ErrTooManyMiddlewares = errors.New("Too many middlewares for the group")
-	ErrReservedRoute      = errors.New("Reserved prefix of routes")
+	ErrConfigSection       = errors.New("Config section does not exist")
+	ErrSettingsKey         = errors.New("Settings key is duplicated")
+	ErrHeaderFlushed       = errors.New("Response headers have been written")
+	ErrTooManyMiddlewares  = errors.New("Too many middlewares for the group")
+	ErrReservedRoute       = errors.New("Reserved prefix of routes")
+	ErrInvalidHost         = errors.New("Invalid pattern of host")
+	ErrRouteNameDuplicated = errors.New("Route name is registered with another path")
+	ErrRouteNotNamed       = errors.New("No route registered with the name")
+	ErrRouteParamsMissing  = errors.New("Missing params of the route")
+	ErrRouteParamsExtra    = errors.New("Too many params of the route")
+	ErrRouteConstraint     = errors.New("Invalid constraint of route param")
+	ErrActionConflicted    = errors.New("Custom action conflicts with routes of the resource")
+	ErrShutdownTimeout     = errors.New("Server shutdown timeout, connections are closed forcibly")
+	ErrUpgradeTimeout      = errors.New("Server upgrade timeout, child process is not ready")
+	ErrUpgradeNotServing   = errors.New("Server upgrade requires a listening server")
+	ErrServerStarted       = errors.New("Server has been started")
+	ErrServerNotStarted    = errors.New("Server has not been started")
+	ErrLimitDisabled       = errors.New("Limit is not enabled by config")
+	ErrLimitInvalid        = errors.New("Limit must not be negative")
+	ErrProxyUntrusted      = errors.New("PROXY protocol requires trusted proxies")
+	ErrCORSCredentials     = errors.New("CORS credentials require explicit origins instead of *")
 )
+
+// A ListenError represents failure of announcing on the address of a listener.
+type ListenError struct {
+	Network string
+	Address string
+	Err     error
+}
+
+func (e *ListenError) Error() string {
+	return fmt.Sprintf("Listen on %s://%s: %v", e.Network, e.Address, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *ListenError) Unwrap() error {
+	return e.Err
+}
+
+// A TLSError represents misconfiguration of certificates, client CAs or client
+// auth of a listener.
+type TLSError struct {
+	Network string
+	Address string
+	Err     error
+}
+
+func (e *TLSError) Error() string {
+	return fmt.Sprintf("TLS of %s://%s: %v", e.Network, e.Address, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *TLSError) Unwrap() error {
+	return e.Err
+}
+
+// A ServeError represents failure of accepting connections for a listener.
+type ServeError struct {
+	Op      string // e.g. Serve, ServeTLS, ServeHTTP2 and ServeH2C
+	Network string
+	Address string
+	Err     error
+}
+
+func (e *ServeError) Error() string {
+	return fmt.Sprintf("%s(%s://%s): %v", e.Op, e.Network, e.Address, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *ServeError) Unwrap() error {
+	return e.Err
+}
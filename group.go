@@ -2,12 +2,14 @@ package gogo
 
 import (
 	"context"
+	"fmt"
 	"math"
 	"net/http"
 	"net/http/httputil"
 	"path"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/dolab/gogo/pkgs/gid"
 	"github.com/dolab/httpdispatch"
@@ -18,9 +20,14 @@ type AppGroup struct {
 	mux sync.RWMutex
 
 	server  *AppServer
+	host    string // empty for the default host
 	prefix  string
+	name    string // name prefix of routes generated by Resource
 	filters []Middleware
 	handler Handler
+	tree    *routeTree
+	timeout *time.Duration // nil means inherited from server
+	options []RouteOption  // applied to routes before options of route
 }
 
 // NewAppGroup creates a new router with specified prefix and server
@@ -36,6 +43,7 @@ func NewAppGroup(prefix string, server *AppServer) *AppGroup {
 		server:  server,
 		prefix:  prefix,
 		handler: dispatcher,
+		tree:    newRouteTree(),
 	}
 }
 
@@ -46,12 +54,25 @@ func (r *AppGroup) NewGroup(prefix string, filters ...Middleware) Grouper {
 
 	return &AppGroup{
 		server:  r.server,
+		host:    r.host,
 		handler: r.handler,
+		tree:    r.tree,
 		prefix:  r.buildPrefix(prefix),
+		name:    r.name,
 		filters: r.buildMiddlewares(filters...),
+		timeout: r.timeout,
+		options: r.options,
 	}
 }
 
+// SetTimeout overwrites handler timeout of server for routes registered after,
+// zero disables the timeout. Groups created by NewGroup inherit it.
+func (r *AppGroup) SetTimeout(timeout time.Duration) {
+	r.mux.Lock()
+	r.timeout = &timeout
+	r.mux.Unlock()
+}
+
 // SetHandler replaces hanlder of AppGroup
 func (r *AppGroup) SetHandler(handler Handler) {
 	r.mux.Lock()
@@ -59,6 +80,85 @@ func (r *AppGroup) SetHandler(handler Handler) {
 	r.mux.Unlock()
 }
 
+// SetNotFound replaces handler for route not found of routes tree, it's shared
+// by all groups of the same host. It restores the default if handler is nil.
+//
+// NOTE: It does nothing if handler of group has been replaced by SetHandler.
+func (r *AppGroup) SetNotFound(handler http.Handler) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	dispatcher, ok := r.handler.(*httpdispatch.Dispatcher)
+	if !ok {
+		return
+	}
+
+	if handler == nil {
+		handler = NewNotFoundHandle(r.server)
+	}
+
+	dispatcher.NotFound = handler
+}
+
+// SetMethodNotAllowed replaces handler for request method not allowed of routes
+// tree, it's shared by all groups of the same host. Requests are responded with
+// NotFound handler if handler is nil, which is the default unless SetAutoOptions.
+//
+// NOTE: It does nothing if handler of group has been replaced by SetHandler.
+func (r *AppGroup) SetMethodNotAllowed(handler http.Handler) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	dispatcher, ok := r.handler.(*httpdispatch.Dispatcher)
+	if !ok {
+		return
+	}
+
+	if handler == nil {
+		dispatcher.HandleMethodNotAllowed = r.tree.isAutoOptions()
+		dispatcher.MethodNotAllowed = NewMethodNotAllowedHandle(r.server)
+		return
+	}
+
+	dispatcher.HandleMethodNotAllowed = true
+	dispatcher.MethodNotAllowed = handler
+}
+
+// SetAutoOptions turns on answering OPTIONS requests automatically with Allow header
+// of methods registered for routes tree, it's shared by all groups of the same host.
+// Requests with method not allowed are responded with 405 and Allow header, and
+// preflight requests are answered with cors policy if not nil.
+//
+// NOTE: It panics with ErrCORSCredentials if cors allows credentials for any origin,
+// and does nothing if handler of group has been replaced by SetHandler.
+func (r *AppGroup) SetAutoOptions(cors *CORSConfig) {
+	var policy *corsPolicy
+	if cors != nil {
+		var err error
+
+		policy, err = newCORSPolicy(cors)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	r.setAutoOptions(policy)
+}
+
+func (r *AppGroup) setAutoOptions(policy *corsPolicy) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	dispatcher, ok := r.handler.(*httpdispatch.Dispatcher)
+	if !ok {
+		return
+	}
+
+	r.tree.enableAutoOptions(policy)
+
+	dispatcher.HandleMethodNotAllowed = true
+}
+
 // Use appends new filters to the end of group
 //
 // TODO: ignore duplicated filters?
@@ -87,59 +187,80 @@ func (r *AppGroup) CleanMiddlewares() {
 }
 
 // OPTIONS is a shortcut of group.Handle("OPTIONS", path, handler)
-func (r *AppGroup) OPTIONS(rpath string, handler Middleware) {
-	r.Handle("OPTIONS", rpath, handler)
+func (r *AppGroup) OPTIONS(rpath string, handler Middleware, opts ...RouteOption) {
+	r.Handle("OPTIONS", rpath, handler, opts...)
 }
 
 // HEAD is a shortcut of group.Handle("HEAD", path, handler)
-func (r *AppGroup) HEAD(rpath string, handler Middleware) {
-	r.Handle("HEAD", rpath, handler)
+func (r *AppGroup) HEAD(rpath string, handler Middleware, opts ...RouteOption) {
+	r.Handle("HEAD", rpath, handler, opts...)
 }
 
 // POST is a shortcut of group.Handle("POST", path, handler)
-func (r *AppGroup) POST(rpath string, handler Middleware) {
-	r.Handle("POST", rpath, handler)
+func (r *AppGroup) POST(rpath string, handler Middleware, opts ...RouteOption) {
+	r.Handle("POST", rpath, handler, opts...)
 }
 
 // GET is a shortcut of group.Handle("GET", path, handler)
-func (r *AppGroup) GET(rpath string, handler Middleware) {
-	r.Handle("GET", rpath, handler)
+func (r *AppGroup) GET(rpath string, handler Middleware, opts ...RouteOption) {
+	r.Handle("GET", rpath, handler, opts...)
 }
 
 // PUT is a shortcut of group.Handle("PUT", path, handler)
-func (r *AppGroup) PUT(rpath string, handler Middleware) {
-	r.Handle("PUT", rpath, handler)
+func (r *AppGroup) PUT(rpath string, handler Middleware, opts ...RouteOption) {
+	r.Handle("PUT", rpath, handler, opts...)
 }
 
 // PATCH is a shortcut of group.Handle("PATCH", path, handler)
-func (r *AppGroup) PATCH(rpath string, handler Middleware) {
-	r.Handle("PATCH", rpath, handler)
+func (r *AppGroup) PATCH(rpath string, handler Middleware, opts ...RouteOption) {
+	r.Handle("PATCH", rpath, handler, opts...)
 }
 
 // DELETE is a shortcut of group.Handle("DELETE", path, handler)
-func (r *AppGroup) DELETE(rpath string, handler Middleware) {
-	r.Handle("DELETE", rpath, handler)
+func (r *AppGroup) DELETE(rpath string, handler Middleware, opts ...RouteOption) {
+	r.Handle("DELETE", rpath, handler, opts...)
 }
 
 // Any is a shortcut for all request methods
-func (r *AppGroup) Any(rpath string, handler Middleware) {
-	r.Handle("GET", rpath, handler)
-	r.Handle("POST", rpath, handler)
-	r.Handle("PUT", rpath, handler)
-	r.Handle("PATCH", rpath, handler)
-	r.Handle("DELETE", rpath, handler)
-	r.Handle("HEAD", rpath, handler)
-	r.Handle("OPTIONS", rpath, handler)
+func (r *AppGroup) Any(rpath string, handler Middleware, opts ...RouteOption) {
+	for _, method := range anyMethods {
+		r.Handle(method, rpath, handler, opts...)
+	}
 }
 
-// Static serves files from the given dir
+// Static serves files from the given dir, it's a shortcut of StaticFS with
+// http.Dir and default options.
 func (r *AppGroup) Static(rpath, root string) {
-	if rpath[len(rpath)-1] != '/' {
+	r.StaticFS(rpath, http.Dir(root), nil)
+}
+
+// StaticFS serves files of fs with options for GET and HEAD, e.g. index fallback
+// for single-page apps, precompressed variants and Cache-Control by extension.
+// Directory listing is disabled unless opts.Browse is true. Filters of group are
+// applied before serving files.
+//
+// NOTE: Any http.FileSystem is acceptable, e.g. http.FS of embedded files.
+func (r *AppGroup) StaticFS(rpath string, fs http.FileSystem, opts *StaticOptions, routeOpts ...RouteOption) {
+	if rpath == "" || rpath[len(rpath)-1] != '/' {
 		rpath += "/"
 	}
 	rpath += "*filepath"
 
-	r.handler.ServeFiles(rpath, http.Dir(root))
+	uri := r.buildPrefix(rpath)
+	files := newStaticFiles(r, fs, opts)
+
+	for _, method := range []string{http.MethodGet, http.MethodHead} {
+		ch := NewContextHandle(
+			nil, r.buildMiddlewares(files.Serve),
+			r.server.RequestRouted, r.server.ResponseReady, r.server.ResponseAlways,
+		)
+		ch.withOptions(r.server, newRouteOptions(r, routeOpts))
+		ch.pkg, ch.ctrl, ch.action = "gogo", "AppGroup", "Static"
+
+		r.handler.Handle(method, uri, ch)
+
+		ch.route = r.addRoute(newRoute(method, uri, ch))
+	}
 }
 
 // Proxy registers a new resource with a *httputil.ReverseProxy
@@ -173,7 +294,17 @@ func (r *AppGroup) Proxy(method string, rpath string, proxy *httputil.ReversePro
 // 		PUT		/article/:article	Article.Update
 // 		DELETE	/article/:article	Article.Destroy
 //
-func (r *AppGroup) Resource(resource string, controller interface{}) Grouper {
+// Custom actions are registered for controllers implementing ControllerCollectionActions
+// and ControllerMemberActions, e.g. POST /article/:article/publish. Collection actions
+// must not conflict with routes of /article/:article with the same method, e.g.
+// GET /article/search conflicts with Show, it panics with ErrActionConflicted.
+//
+// Routes are named by resource and action, e.g. article.show, and routes of
+// nested resources are prefixed with names of parents, e.g. article.comments.show.
+// Member actions are named within member, e.g. article.member.publish, so they
+// never collide with collection actions of the same name. Use RouteName to
+// overwrite the resource name.
+func (r *AppGroup) Resource(resource string, controller interface{}, opts ...RouteOption) Grouper {
 	resource = strings.TrimSuffix(resource, "/")
 	if resource[0] != '/' {
 		resource = "/" + resource
@@ -200,79 +331,171 @@ func (r *AppGroup) Resource(resource string, controller interface{}) Grouper {
 
 	resourceSpec = resource + "/:" + idSuffix
 
+	// for named routes, e.g. article.show
+	name := newRouteOptions(r, opts).name
+	if name == "" {
+		name = strings.Replace(strings.Trim(resource, "/"), "/", ".", -1)
+	}
+	if r.name != "" {
+		name = r.name + "." + name
+	}
+
+	named := func(action string) []RouteOption {
+		return append(opts[:len(opts):len(opts)], RouteName(name+"."+action))
+	}
+
 	// for user-defined dispatch route
 	dispatch, ok := controller.(ControllerDispatch)
 	if ok {
-		r.Any(resource, dispatch.DISPATCH)
-		r.Any(resourceSpec, dispatch.DISPATCH)
+		for _, method := range anyMethods {
+			r.handle(method, resource, dispatch.DISPATCH, controller, named("index"))
+			r.handle(method, resourceSpec, dispatch.DISPATCH, controller, named("show"))
+		}
 
-		return r.NewGroup(resourceSpec)
+		r.handleActions(resource, resourceSpec, controller, named)
+
+		return r.newResourceGroup(resourceSpec, name)
 	}
 
 	// for GET /resource
 	index, ok := controller.(ControllerIndex)
 	if ok {
-		r.GET(resource, index.Index)
+		r.handle(http.MethodGet, resource, index.Index, controller, named("index"))
 	}
 
 	// for POST /resource
 	create, ok := controller.(ControllerCreate)
 	if ok {
-		r.POST(resource, create.Create)
+		r.handle(http.MethodPost, resource, create.Create, controller, named("create"))
 	}
 
 	// for HEAD /resource/:resource
 	head, ok := controller.(ControllerExplore)
 	if ok {
-		r.HEAD(resourceSpec, head.Explore)
+		r.handle(http.MethodHead, resourceSpec, head.Explore, controller, named("explore"))
 	}
 
 	// for GET /resource/:resource
 	show, ok := controller.(ControllerShow)
 	if ok {
-		r.GET(resourceSpec, show.Show)
+		r.handle(http.MethodGet, resourceSpec, show.Show, controller, named("show"))
 	}
 
 	// for PUT /resource/:resource
 	update, ok := controller.(ControllerUpdate)
 	if ok {
-		r.PUT(resourceSpec, update.Update)
+		r.handle(http.MethodPut, resourceSpec, update.Update, controller, named("update"))
 	}
 
 	// for DELETE /resource/:resource
 	delete, ok := controller.(ControllerDestroy)
 	if ok {
-		r.DELETE(resourceSpec, delete.Destroy)
+		r.handle(http.MethodDelete, resourceSpec, delete.Destroy, controller, named("destroy"))
 	}
 
-	return r.NewGroup(resourceSpec)
+	r.handleActions(resource, resourceSpec, controller, named)
+
+	return r.newResourceGroup(resourceSpec, name)
+}
+
+// handleActions registers custom actions of controller for collection and member of resource
+func (r *AppGroup) handleActions(resource, resourceSpec string, controller interface{}, named func(string) []RouteOption) {
+	// for METHOD /resource/action
+	if collection, ok := controller.(ControllerCollectionActions); ok {
+		for _, action := range collection.CollectionActions() {
+			r.handleAction(action.Method, joinActionPath(resource, action.Path), action.Handler, controller, named(actionName(action)))
+		}
+	}
+
+	// for METHOD /resource/:resource/action
+	if member, ok := controller.(ControllerMemberActions); ok {
+		for _, action := range member.MemberActions() {
+			r.handleAction(action.Method, joinActionPath(resourceSpec, action.Path), action.Handler, controller, named("member."+actionName(action)))
+		}
+	}
+}
+
+// handleAction registers custom action of resource, it panics with ErrActionConflicted
+// instead of panic of router if the path conflicts with routes registered.
+func (r *AppGroup) handleAction(method, uri string, handler Middleware, controller interface{}, opts []RouteOption) {
+	defer func() {
+		if v := recover(); v != nil {
+			// NOTE: router panics with string
+			if reason, ok := v.(string); ok {
+				panic(fmt.Errorf("%w: %s %s, %s", ErrActionConflicted, method, uri, reason))
+			}
+
+			panic(v)
+		}
+	}()
+
+	r.handle(method, uri, handler, controller, opts)
+}
+
+// newResourceGroup returns a new *AppGroup for nested resources with name prefix
+func (r *AppGroup) newResourceGroup(prefix, name string) Grouper {
+	group := r.NewGroup(prefix).(*AppGroup)
+	group.name = name
+
+	return group
 }
 
 // HandlerFunc registers a new resource with http.HandlerFunc
-func (r *AppGroup) HandlerFunc(method, uri string, handler http.HandlerFunc) {
-	r.Handler(method, uri, handler)
+func (r *AppGroup) HandlerFunc(method, uri string, handler http.HandlerFunc, opts ...RouteOption) {
+	r.Handler(method, uri, handler, opts...)
 }
 
 // Handler registers a new resource with http.Handler
-func (r *AppGroup) Handler(method, uri string, handler http.Handler) {
-	uri = r.buildPrefix(uri)
+func (r *AppGroup) Handler(method, uri string, handler http.Handler, opts ...RouteOption) {
+	uri, constraints := parseConstraints(r.buildPrefix(uri))
 	filters := r.buildMiddlewares()
 
-	r.handler.Handle(method, uri, NewContextHandle(
-		handler.ServeHTTP, filters,
+	// NOTE: use func of http.HandlerFunc directly for metadata resolving
+	fn, ok := handler.(http.HandlerFunc)
+	if !ok {
+		fn = handler.ServeHTTP
+	}
+
+	ch := NewContextHandle(
+		fn, filters,
 		r.server.RequestRouted, r.server.ResponseReady, r.server.ResponseAlways,
-	))
+	)
+	ch.withOptions(r.server, newRouteOptions(r, opts))
+	ch.withConstraints(constraints, http.HandlerFunc(r.serveNotFound))
+
+	r.handler.Handle(method, uri, ch)
+
+	ch.route = r.addRoute(newRoute(method, uri, ch))
+}
+
+// Handle registers a new resource. Params of path can be constrained by builtin
+// type or regular expression, e.g. /users/:id<int> and /files/:name<[a-z0-9-]+>,
+// requests mismatched are responded with NotFound handler before any filter runs.
+func (r *AppGroup) Handle(method string, uri string, filter Middleware, opts ...RouteOption) {
+	r.handle(method, uri, filter, nil, opts)
 }
 
-// Handle registers a new resource
-func (r *AppGroup) Handle(method string, uri string, filter Middleware) {
-	uri = r.buildPrefix(uri)
+// handle registers a new resource with metadata of controller if given.
+func (r *AppGroup) handle(method string, uri string, filter Middleware, controller interface{}, opts []RouteOption) {
+	uri, constraints := parseConstraints(r.buildPrefix(uri))
 	filters := r.buildMiddlewares(filter)
 
-	r.handler.Handle(method, uri, NewContextHandle(
+	ch := NewContextHandle(
 		nil, filters,
 		r.server.RequestRouted, r.server.ResponseReady, r.server.ResponseAlways,
-	))
+	)
+
+	ch.withOptions(r.server, newRouteOptions(r, opts))
+	ch.withConstraints(constraints, http.HandlerFunc(r.serveNotFound))
+
+	// NOTE: actions of resource are method values of interfaces, e.g. ControllerIndex.Index
+	if controller != nil {
+		ch.pkg, ch.ctrl = controllerNames(controller)
+	}
+
+	r.handler.Handle(method, uri, ch)
+
+	ch.route = r.addRoute(newRoute(method, uri, ch))
 }
 
 // MountRPC registers all rpc services
@@ -283,22 +506,33 @@ func (r *AppGroup) MountRPC(method string, svc RPCServicer) {
 	for uri, handler := range svc.ServiceRegistry(prefix) {
 		filters := r.buildMiddlewares(handler)
 
-		r.handler.Handle(method, uri, NewContextHandle(
+		ch := NewContextHandle(
 			nil, filters,
 			r.server.RequestRouted, r.server.ResponseReady, r.server.ResponseAlways,
-		))
+		)
+		ch.withOptions(r.server, newRouteOptions(r, nil))
+
+		r.handler.Handle(method, uri, ch)
+
+		ch.route = r.addRoute(newRoute(method, uri, ch))
 	}
 }
 
 // MockHandle mocks a new resource with specified response and handler, useful for testing
 func (r *AppGroup) MockHandle(method string, rpath string, recorder http.ResponseWriter, handler Middleware) {
-	uri := r.buildPrefix(rpath)
+	uri, constraints := parseConstraints(r.buildPrefix(rpath))
 	filters := r.buildMiddlewares(handler)
 
-	r.handler.Handle(method, uri, NewFakeHandle(
+	fh := NewFakeHandle(
 		nil, filters, recorder,
 		r.server.RequestRouted, r.server.ResponseReady, r.server.ResponseAlways,
-	))
+	)
+	fh.withOptions(r.server, newRouteOptions(r, nil))
+	fh.withConstraints(constraints, http.HandlerFunc(r.serveNotFound))
+
+	r.handler.Handle(method, uri, fh)
+
+	fh.route = r.addRoute(newRoute(method, uri, fh.ContextHandle))
 }
 
 // ServeHTTP implements the http.Handler interface
@@ -330,13 +564,50 @@ func (r *AppGroup) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	r.handler.ServeHTTP(resp, req)
+	group := r.lookupGroup(req)
+	if group.tree.serve(resp, req, group.handler) {
+		return
+	}
+
+	group.handler.ServeHTTP(resp, req)
+}
+
+// addRoute records route with host of group, and returns it
+func (r *AppGroup) addRoute(route *Route) *Route {
+	route.Host = r.host
+
+	r.server.addRoute(route)
+
+	return route
+}
+
+// reservedRoutes defines paths of internal routes registered by server
+var reservedRoutes = []string{
+	GogoHealthz,
+	GogoReadyz,
+	GogoRoutes,
+	GogoDebug,
+	GogoAdmin,
+}
+
+// isReservedRoute returns true if uri is path of internal routes or under them,
+// other paths with GogoPrefix are available for applications.
+func isReservedRoute(uri string) bool {
+	for _, reserved := range reservedRoutes {
+		reserved = strings.TrimSuffix(reserved, "/")
+
+		if uri == reserved || strings.HasPrefix(uri, reserved+"/") {
+			return true
+		}
+	}
+
+	return false
 }
 
 func (r *AppGroup) buildPrefix(suffix string) (prefix string) {
 	defer func() {
 		// assert for internal routes
-		if strings.HasPrefix(prefix, GogoHealthz) {
+		if isReservedRoute(prefix) {
 			panic(ErrReservedRoute)
 		}
 	}()
@@ -369,4 +640,45 @@ func (r *AppGroup) registerHealthz() {
 
 	r.handler.Handle(http.MethodGet, GogoHealthz, handler)
 	r.handler.Handle(http.MethodPost, GogoHealthz, handler)
+
+	readyz := NewReadyzHandle(r.server)
+
+	r.handler.Handle(http.MethodGet, GogoReadyz, readyz)
+	r.handler.Handle(http.MethodPost, GogoReadyz, readyz)
+}
+
+func (r *AppGroup) registerRoutes() {
+	r.handler.Handle(http.MethodGet, GogoRoutes, NewRoutesHandle(r.server))
+}
+
+func (r *AppGroup) registerDebug(config *DebugConfig) error {
+	handler, err := NewDebugHandle(config)
+	if err != nil {
+		return err
+	}
+
+	// pprof.Symbol accepts POST for symbol lookups
+	r.handler.Handle(http.MethodGet, GogoDebug+"*debug", handler)
+	r.handler.Handle(http.MethodPost, GogoDebug+"*debug", handler)
+
+	return nil
+}
+
+func (r *AppGroup) registerAdmin(config *AdminConfig) error {
+	reload, err := NewReloadHandle(r.server, config)
+	if err != nil {
+		return err
+	}
+
+	r.handler.Handle(http.MethodGet, GogoAdmin+"reload", reload)
+
+	limits, err := NewLimitsHandle(r.server, config)
+	if err != nil {
+		return err
+	}
+
+	r.handler.Handle(http.MethodGet, GogoAdmin+"limits", limits)
+	r.handler.Handle(http.MethodPut, GogoAdmin+"limits", limits)
+
+	return nil
 }
@@ -1,6 +1,7 @@
 package gogo
 
 import (
+	"errors"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
@@ -290,6 +291,169 @@ func Test_Group_Resource(t *testing.T) {
 	request.AssertContains("not found")
 }
 
+type testOrderController struct{}
+
+func (t *testOrderController) Show(ctx *Context) {
+	ctx.Text("GET /orders/" + ctx.Params.Get("orders"))
+}
+
+func (t *testOrderController) Cancel(ctx *Context) {
+	ctx.Text("POST /orders/" + ctx.Params.Get("orders") + "/cancel")
+}
+
+func (t *testOrderController) Batch(ctx *Context) {
+	ctx.Text("PUT /orders/batch")
+}
+
+func (t *testOrderController) MemberActions() []ControllerAction {
+	return []ControllerAction{
+		{Method: http.MethodPost, Path: "cancel", Handler: t.Cancel},
+		{Method: http.MethodGet, Path: "/items/:item<int>", Handler: t.Show, Name: "order.items"},
+	}
+}
+
+func (t *testOrderController) CollectionActions() []ControllerAction {
+	return []ControllerAction{
+		{Method: http.MethodPut, Path: "batch", Handler: t.Batch},
+	}
+}
+
+func Test_Group_ResourceWithActions(t *testing.T) {
+	it := assert.New(t)
+	server := fakeServer()
+
+	var filtered int
+	group := server.NewGroup("/v1", func(ctx *Context) {
+		filtered++
+
+		ctx.Next()
+	})
+	group.Resource("/orders", &testOrderController{})
+
+	testCases := []struct {
+		method   string
+		uri      string
+		status   int
+		expected string
+	}{
+		{http.MethodGet, "/v1/orders/1", http.StatusOK, "GET /orders/1"},
+		{http.MethodPost, "/v1/orders/1/cancel", http.StatusOK, "POST /orders/1/cancel"},
+		{http.MethodPut, "/v1/orders/batch", http.StatusOK, "PUT /orders/batch"},
+		{http.MethodGet, "/v1/orders/1/items/2", http.StatusOK, "GET /orders/1"},
+		{http.MethodGet, "/v1/orders/1/items/x", http.StatusNotFound, "not found"},
+		{http.MethodGet, "/v1/orders/1/cancel", http.StatusNotFound, "not found"},
+	}
+	for _, testCase := range testCases {
+		filtered = 0
+
+		r, _ := http.NewRequest(testCase.method, testCase.uri, nil)
+		w := httptest.NewRecorder()
+
+		server.ServeHTTP(w, r)
+		it.Equal(testCase.status, w.Code, testCase.uri)
+		it.Contains(w.Body.String(), testCase.expected, testCase.uri)
+
+		if testCase.status == http.StatusOK {
+			it.Equal(1, filtered, testCase.uri)
+		}
+	}
+
+	// it should register named routes
+	uri, err := server.URLFor("orders.member.cancel", 1)
+	if it.Nil(err) {
+		it.Equal("/v1/orders/1/cancel", uri)
+	}
+
+	uri, err = server.URLFor("orders.batch")
+	if it.Nil(err) {
+		it.Equal("/v1/orders/batch", uri)
+	}
+
+	uri, err = server.URLFor("orders.member.order.items", 1, 2)
+	if it.Nil(err) {
+		it.Equal("/v1/orders/1/items/2", uri)
+	}
+
+	routes := server.Routes()
+	for _, route := range routes {
+		if route.Name == "orders.member.cancel" {
+			it.Equal("*testOrderController", route.Controller)
+			it.Equal("Cancel", route.Action)
+			it.Equal(1, route.Middlewares)
+		}
+	}
+}
+
+type testExportController struct{}
+
+func (t *testExportController) Show(ctx *Context) {
+	ctx.Text("GET /reports/" + ctx.Params.Get("reports"))
+}
+
+func (t *testExportController) ExportAll(ctx *Context) {
+	ctx.Text("GET /reports/export")
+}
+
+func (t *testExportController) Export(ctx *Context) {
+	ctx.Text("GET /reports/" + ctx.Params.Get("reports") + "/export")
+}
+
+func (t *testExportController) CollectionActions() []ControllerAction {
+	return []ControllerAction{
+		{Method: http.MethodPost, Path: "export", Handler: t.ExportAll},
+	}
+}
+
+func (t *testExportController) MemberActions() []ControllerAction {
+	return []ControllerAction{
+		{Method: http.MethodGet, Path: "export", Handler: t.Export},
+	}
+}
+
+type testConflictController struct {
+	testExportController
+}
+
+func (t *testConflictController) CollectionActions() []ControllerAction {
+	return []ControllerAction{
+		{Method: http.MethodGet, Path: "search", Handler: t.ExportAll},
+	}
+}
+
+func Test_Group_ResourceWithActionsOfSameName(t *testing.T) {
+	it := assert.New(t)
+	server := fakeServer()
+
+	it.NotPanics(func() {
+		server.Resource("/reports", &testExportController{})
+	})
+
+	uri, err := server.URLFor("reports.export")
+	if it.Nil(err) {
+		it.Equal("/reports/export", uri)
+	}
+
+	uri, err = server.URLFor("reports.member.export", 1)
+	if it.Nil(err) {
+		it.Equal("/reports/1/export", uri)
+	}
+}
+
+func Test_Group_ResourceWithActionsConflicted(t *testing.T) {
+	it := assert.New(t)
+	server := fakeServer()
+
+	defer func() {
+		err, ok := recover().(error)
+		if it.True(ok) {
+			it.True(errors.Is(err, ErrActionConflicted))
+			it.Contains(err.Error(), "GET /reports/search")
+		}
+	}()
+
+	server.Resource("/reports", &testConflictController{})
+}
+
 type testGroupMemberController struct{}
 
 func (t *testGroupMemberController) Index(ctx *Context) {
@@ -350,4 +514,34 @@ func Test_GroupWithReservedRoute(t *testing.T) {
 			ctx.SetStatus(http.StatusInternalServerError)
 		})
 	})
+
+	// it should reserve all internal routes
+	for _, uri := range []string{"/-/readyz", "/-/routes", "/-/debug/pprof/", "/-/admin/reload"} {
+		it.Panics(func() {
+			server.GET(uri, func(ctx *Context) {
+				ctx.SetStatus(http.StatusInternalServerError)
+			})
+		}, uri)
+	}
+
+	it.Panics(func() {
+		server.NewGroup("/-/debug").GET("/custom", func(ctx *Context) {
+			ctx.SetStatus(http.StatusInternalServerError)
+		})
+	})
+
+	// it should work for other routes with prefix
+	it.NotPanics(func() {
+		server.NewGroup("/-").GET("/custom", func(ctx *Context) {
+			ctx.Text("custom")
+		})
+	})
+
+	ts := httptesting.NewServer(server, false)
+	defer ts.Close()
+
+	request := ts.New(t)
+	request.Get("/-/custom")
+	request.AssertOK()
+	request.AssertContains("custom")
 }
@@ -1,14 +1,18 @@
 package gogo
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"reflect"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/dolab/gogo/internal/params"
 	"github.com/dolab/gogo/pkgs/hooks"
+	"github.com/dolab/gogo/pkgs/metadata"
 	"github.com/dolab/httpdispatch"
 )
 
@@ -23,6 +27,14 @@ type ContextHandle struct {
 	requestRouted  *hooks.HookList
 	responseReady  *hooks.HookList
 	responseAlways *hooks.HookList
+	deadline       *handlerDeadline // deadline of server
+	timeout        *time.Duration   // overwrites timeout of deadline if not nil
+	name           string           // name of route, empty for unnamed
+	names          *routeNames      // named routes of server for URLFor
+	constraints    paramConstraints // constraints of path params
+	notFound       http.Handler     // serves requests mismatched constraints
+	meta           metadata.Metadata
+	route          *Route // registered with server
 }
 
 // NewContextHandle returns new *ContextHandle with handler and metadata
@@ -74,8 +86,53 @@ func NewContextHandle(handler http.HandlerFunc, filters []Middleware,
 	}
 }
 
+// withOptions applies deadline and named routes of server and options of route
+func (ch *ContextHandle) withOptions(server *AppServer, opts *routeOptions) {
+	ch.deadline = &server.localDeadline
+	ch.names = &server.localNames
+	ch.timeout = opts.timeout
+	ch.name = opts.name
+	ch.meta = opts.meta
+}
+
+// withConstraints applies constraints of path params, requests mismatched are
+// served by notFound.
+func (ch *ContextHandle) withConstraints(constraints paramConstraints, notFound http.Handler) {
+	ch.constraints = constraints
+	ch.notFound = notFound
+}
+
+// Timeout returns timeout of handler, zero means no timeout.
+func (ch *ContextHandle) Timeout() time.Duration {
+	if ch.timeout != nil {
+		return *ch.timeout
+	}
+
+	if ch.deadline != nil {
+		return ch.deadline.timeout
+	}
+
+	return 0
+}
+
 // Handle implements httpdispatch.Handler interface
 func (ch *ContextHandle) Handle(w http.ResponseWriter, r *http.Request, ps httpdispatch.Params) {
+	// fall through to route not found if params mismatched constraints
+	if !ch.constraints.match(ps) {
+		ch.notFound.ServeHTTP(w, r)
+		return
+	}
+
+	// bound request with route for hooks and interceptors
+	if ch.route != nil {
+		rctx := context.WithValue(r.Context(), ctxRouteKey, ch.route)
+		if ch.meta != nil {
+			rctx = metadata.NewContext(rctx, ch.meta)
+		}
+
+		r = r.WithContext(rctx)
+	}
+
 	// invoke ResponseAlways
 	defer ch.responseAlways.Run(w, r)
 
@@ -84,9 +141,31 @@ func (ch *ContextHandle) Handle(w http.ResponseWriter, r *http.Request, ps httpd
 		return
 	}
 
+	// bound request with deadline of handler, and respond as soon as it exceeded
+	// if handler has written nothing.
+	//
+	// NOTE: handlers are not interrupted, they should return as soon as ctx.Request.Context() done.
+	if timeout := ch.Timeout(); timeout > 0 {
+		rctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		r = r.WithContext(rctx)
+
+		dw := newDeadlineWriter(w, r, ch.deadline)
+		defer dw.finish()
+
+		stop := context.AfterFunc(rctx, dw.expire)
+		defer stop()
+
+		w = dw
+	}
+
 	ctx := contextNew(w, r, params.NewParams(r, ps), ch.pkg, ch.ctrl, ch.action)
 	defer contextReuse(ctx)
 
+	ctx.names = ch.names
+	ctx.route = ch.route
+
 	if ch.handler == nil {
 		ctx.run(nil, ch.filters, ch.responseReady)
 	} else {
@@ -94,17 +173,47 @@ func (ch *ContextHandle) Handle(w http.ResponseWriter, r *http.Request, ps httpd
 	}
 }
 
-// HealthzHandle defines a wrapper of handler for /-/healthz
-type HealthzHandle struct{}
+// HealthzHandle defines a wrapper of handler for /-/healthz and /-/readyz
+type HealthzHandle struct {
+	server    *AppServer
+	readiness bool
+}
 
-// NewHealthzHandle creates a new handler with route not found
+// NewHealthzHandle creates a new handler for liveness checking
 func NewHealthzHandle(server *AppServer) *HealthzHandle {
-	return &HealthzHandle{}
+	return &HealthzHandle{
+		server: server,
+	}
 }
 
-// Handle implements httpdispatch.Handler interface
+// NewReadyzHandle creates a new handler for readiness checking
+func NewReadyzHandle(server *AppServer) *HealthzHandle {
+	return &HealthzHandle{
+		server:    server,
+		readiness: true,
+	}
+}
+
+// Handle responses report of health checks in JSON, it responses 503 if any
+// check failed or server is draining.
 func (h *HealthzHandle) Handle(w http.ResponseWriter, r *http.Request, ps httpdispatch.Params) {
-	w.WriteHeader(http.StatusOK)
+	report := &HealthReport{
+		Status: HealthStatusOK,
+	}
+	if h.server != nil {
+		report = h.server.Healthz(r.Context(), h.readiness)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	if report.IsHealthy() {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	json.NewEncoder(w).Encode(report)
 }
 
 // FakeHandle defines a wrapper of handler for testing
@@ -132,6 +241,138 @@ func (ch *FakeHandle) Handle(w http.ResponseWriter, r *http.Request, params http
 	ch.ContextHandle.Handle(ch.recorder, r, params)
 }
 
+// RoutesHandle defines a wrapper of handler for /-/routes
+type RoutesHandle struct {
+	server *AppServer
+}
+
+// NewRoutesHandle creates a new handler for route table
+func NewRoutesHandle(server *AppServer) *RoutesHandle {
+	return &RoutesHandle{
+		server: server,
+	}
+}
+
+// Handle responses all routes of server in JSON
+func (h *RoutesHandle) Handle(w http.ResponseWriter, r *http.Request, ps httpdispatch.Params) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(h.server.Routes())
+}
+
+// ReloadHandle defines a wrapper of handler for /-/admin/reload
+type ReloadHandle struct {
+	server *AppServer
+	guard  *adminGuard
+}
+
+// NewReloadHandle creates a new handler for status of interceptors reloading
+// protected by token and allowlist of config.
+func NewReloadHandle(server *AppServer, config *AdminConfig) (*ReloadHandle, error) {
+	if config == nil {
+		config = &AdminConfig{}
+	}
+
+	guard, err := newAdminGuard(config.Token, config.Allowed)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReloadHandle{
+		server: server,
+		guard:  guard,
+	}, nil
+}
+
+// Handle responses status of the last reloading in JSON
+func (h *ReloadHandle) Handle(w http.ResponseWriter, r *http.Request, ps httpdispatch.Params) {
+	if !h.guard.Allow(r) {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	status := h.server.ReloadStatus()
+	if status == nil {
+		status = &ReloadStatus{
+			Filename: h.server.interceptorsFilename(),
+			Status:   ReloadStatusNever,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(status)
+}
+
+// LimitsHandle defines a wrapper of handler for /-/admin/limits
+type LimitsHandle struct {
+	server *AppServer
+	guard  *adminGuard
+}
+
+// NewLimitsHandle creates a new handler for reading and updating limits of server
+// protected by token and allowlist of config.
+func NewLimitsHandle(server *AppServer, config *AdminConfig) (*LimitsHandle, error) {
+	if config == nil {
+		config = &AdminConfig{}
+	}
+
+	guard, err := newAdminGuard(config.Token, config.Allowed)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LimitsHandle{
+		server: server,
+		guard:  guard,
+	}, nil
+}
+
+// Handle responses limits of server in JSON, it updates limits with JSON encoded
+// LimitsUpdate for PUT request.
+func (h *LimitsHandle) Handle(w http.ResponseWriter, r *http.Request, ps httpdispatch.Params) {
+	if !h.guard.Allow(r) {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	if r.Method == http.MethodPut {
+		var update LimitsUpdate
+
+		err := json.NewDecoder(r.Body).Decode(&update)
+		if err == nil && update.TTL < 0 {
+			err = ErrLimitInvalid
+		}
+		if err == nil {
+			actor := fmt.Sprintf("%s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+
+			err = h.server.adjustLimits(&update, time.Duration(update.TTL)*time.Second, actor)
+		}
+
+		switch err {
+		case nil:
+			// ignore
+
+		case ErrLimitDisabled:
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+
+		default:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(h.server.Limits())
+}
+
 // NotFoundHandle defines a wrapper of handler for route not found
 type NotFoundHandle struct {
 	*ContextHandle
@@ -162,6 +403,14 @@ type MethodNotAllowedHandle struct {
 // NewMethodNotAllowedHandle creates a new handler with request method not allowed
 func NewMethodNotAllowedHandle(server *AppServer) *MethodNotAllowedHandle {
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// NOTE: Allow header is set by httpdispatch in random order
+		if allow := w.Header().Get("Allow"); allow != "" {
+			methods := strings.Split(allow, ", ")
+			sortMethods(methods)
+
+			w.Header().Set("Allow", strings.Join(methods, ", "))
+		}
+
 		http.Error(w, fmt.Sprintf("Request(%s %s): method not allowed", r.Method, r.URL.RequestURI()), http.StatusMethodNotAllowed)
 	})
 	hook := &hooks.HookList{}
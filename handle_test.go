@@ -2,6 +2,7 @@ package gogo
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -197,3 +198,65 @@ func Benchmark_MethodNotAllowedHandle(b *testing.B) {
 		h.ServeHTTP(w, r)
 	}
 }
+
+func Test_HealthzHandle(t *testing.T) {
+	it := assert.New(t)
+	server := fakeHealthzServer()
+
+	handler := NewHealthzHandle(server)
+	r, _ := http.NewRequest("GET", GogoHealthz, nil)
+
+	w := httptest.NewRecorder()
+	handler.Handle(w, r, nil)
+	it.Equal(http.StatusOK, w.Code)
+	it.Equal("application/json", w.Header().Get("Content-Type"))
+	it.Equal(`{"status":"ok"}`+"\n", w.Body.String())
+
+	// it should be unhealthy when draining
+	server.localDraining = 1
+
+	w = httptest.NewRecorder()
+	handler.Handle(w, r, nil)
+	it.Equal(http.StatusServiceUnavailable, w.Code)
+	it.Equal(`{"status":"draining"}`+"\n", w.Body.String())
+}
+
+func Test_ReadyzHandle(t *testing.T) {
+	it := assert.New(t)
+	server := fakeHealthzServer()
+	server.WithHealthChecks(
+		HealthCheck{
+			Name: "db",
+			Checker: HealthCheckerFunc(func(ctx context.Context) error {
+				return nil
+			}),
+			Liveness: true,
+		},
+		HealthCheck{
+			Name: "upstream",
+			Checker: HealthCheckerFunc(func(ctx context.Context) error {
+				return errors.New("unreachable")
+			}),
+		},
+	)
+
+	// it should run liveness checks only
+	handler := NewHealthzHandle(server)
+	r, _ := http.NewRequest("GET", GogoHealthz, nil)
+
+	w := httptest.NewRecorder()
+	handler.Handle(w, r, nil)
+	it.Equal(http.StatusOK, w.Code)
+	it.Contains(w.Body.String(), `"name":"db"`)
+	it.NotContains(w.Body.String(), `"name":"upstream"`)
+
+	// it should run all checks
+	handler = NewReadyzHandle(server)
+	r, _ = http.NewRequest("GET", GogoReadyz, nil)
+
+	w = httptest.NewRecorder()
+	handler.Handle(w, r, nil)
+	it.Equal(http.StatusServiceUnavailable, w.Code)
+	it.Contains(w.Body.String(), `"status":"fail"`)
+	it.Contains(w.Body.String(), `"error":"unreachable"`)
+}
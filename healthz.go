@@ -0,0 +1,177 @@
+package gogo
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// status of health checks
+const (
+	HealthStatusOK       = "ok"
+	HealthStatusFail     = "fail"
+	HealthStatusDraining = "draining"
+)
+
+// A HealthChecker defines checking of server dependencies, e.g. db ping, disk
+// space and reachability of upstreams.
+type HealthChecker interface {
+	Check(ctx context.Context) error
+}
+
+// HealthCheckerFunc is an adapter to allow the use of ordinary functions as HealthChecker.
+type HealthCheckerFunc func(ctx context.Context) error
+
+// Check implements HealthChecker
+func (fn HealthCheckerFunc) Check(ctx context.Context) error {
+	return fn(ctx)
+}
+
+// A HealthCheck defines a named checker with options
+type HealthCheck struct {
+	Name     string
+	Checker  HealthChecker
+	Timeout  time.Duration // timeout of per check, DefaultHealthTimeout is used if zero
+	Cache    time.Duration // result is reused within the duration, zero means no cache
+	Liveness bool          // checks for both /-/healthz and /-/readyz, it's for /-/readyz only by default
+}
+
+// A HealthResult defines result of a health check
+type HealthResult struct {
+	Name    string  `json:"name"`
+	Status  string  `json:"status"`
+	Latency float64 `json:"latency_ms"`
+	Error   string  `json:"error,omitempty"`
+	Cached  bool    `json:"cached,omitempty"`
+}
+
+// A HealthReport defines report of health checks
+type HealthReport struct {
+	Status string          `json:"status"`
+	Checks []*HealthResult `json:"checks,omitempty"`
+}
+
+// IsHealthy returns true if all checks passed and server is not draining
+func (report *HealthReport) IsHealthy() bool {
+	return report.Status == HealthStatusOK
+}
+
+// healthCheck caches the last result of HealthCheck
+type healthCheck struct {
+	HealthCheck
+
+	mux       sync.Mutex
+	checkedAt time.Time
+	result    HealthResult
+}
+
+// check runs checker or returns the cached result, the checker runs without
+// lock held so a hung checker never blocks concurrent probes.
+func (hc *healthCheck) check(ctx context.Context) *HealthResult {
+	hc.mux.Lock()
+	if hc.Cache > 0 && !hc.checkedAt.IsZero() && time.Since(hc.checkedAt) < hc.Cache {
+		result := hc.result
+		result.Cached = true
+
+		hc.mux.Unlock()
+		return &result
+	}
+	hc.mux.Unlock()
+
+	timeout := hc.Timeout
+	if timeout <= 0 {
+		timeout = time.Duration(DefaultHealthTimeout) * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+
+	err := invokeComponent(ctx, hc.Checker.Check)
+
+	checkedAt := time.Now()
+
+	result := HealthResult{
+		Name:    hc.Name,
+		Status:  HealthStatusOK,
+		Latency: float64(checkedAt.Sub(start)) / float64(time.Millisecond),
+	}
+	if err != nil {
+		result.Status = HealthStatusFail
+		result.Error = err.Error()
+	}
+
+	hc.mux.Lock()
+	hc.checkedAt = checkedAt
+	hc.result = result
+	hc.mux.Unlock()
+
+	return &result
+}
+
+// WithHealthChecks registers named checkers for /-/healthz and /-/readyz.
+func (s *AppServer) WithHealthChecks(checks ...HealthCheck) {
+	s.localMux.Lock()
+	defer s.localMux.Unlock()
+
+	for _, check := range checks {
+		s.localChecks = append(s.localChecks, &healthCheck{
+			HealthCheck: check,
+		})
+	}
+}
+
+// Healthz runs checks of liveness concurrently and returns report of them.
+// It runs all checks if readiness is true.
+//
+// NOTE: The status of report is draining if server is shutting down.
+func (s *AppServer) Healthz(ctx context.Context, readiness bool) *HealthReport {
+	s.localMux.RLock()
+	checks := s.localChecks
+	s.localMux.RUnlock()
+
+	report := &HealthReport{
+		Status: HealthStatusOK,
+	}
+
+	if !readiness {
+		var liveness []*healthCheck
+		for _, check := range checks {
+			if check.Liveness {
+				liveness = append(liveness, check)
+			}
+		}
+
+		checks = liveness
+	}
+
+	if len(checks) > 0 {
+		report.Checks = make([]*HealthResult, len(checks))
+	}
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+
+		go func(i int, check *healthCheck) {
+			defer wg.Done()
+
+			report.Checks[i] = check.check(ctx)
+		}(i, check)
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Status != HealthStatusOK {
+			report.Status = HealthStatusFail
+			break
+		}
+	}
+
+	if s.isDraining() {
+		report.Status = HealthStatusDraining
+	}
+
+	return report
+}
@@ -0,0 +1,106 @@
+package gogo
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golib/assert"
+)
+
+func Test_ServerHealthz(t *testing.T) {
+	it := assert.New(t)
+
+	var counter int64
+
+	server := fakeHealthzServer()
+	server.WithHealthChecks(
+		HealthCheck{
+			Name: "cached",
+			Checker: HealthCheckerFunc(func(ctx context.Context) error {
+				atomic.AddInt64(&counter, 1)
+
+				return nil
+			}),
+			Cache:    time.Minute,
+			Liveness: true,
+		},
+		HealthCheck{
+			Name: "timeout",
+			Checker: HealthCheckerFunc(func(ctx context.Context) error {
+				<-ctx.Done()
+
+				return ctx.Err()
+			}),
+			Timeout: 10 * time.Millisecond,
+		},
+	)
+
+	report := server.Healthz(context.Background(), false)
+	if it.True(report.IsHealthy()) && it.Len(report.Checks, 1) {
+		it.Equal("cached", report.Checks[0].Name)
+		it.False(report.Checks[0].Cached)
+	}
+
+	// it should reuse cached result
+	report = server.Healthz(context.Background(), true)
+	if it.False(report.IsHealthy()) && it.Len(report.Checks, 2) {
+		it.True(report.Checks[0].Cached)
+		it.Equal(int64(1), atomic.LoadInt64(&counter))
+
+		// it should fail with timeout
+		it.Equal(HealthStatusFail, report.Checks[1].Status)
+		it.Equal(context.DeadlineExceeded.Error(), report.Checks[1].Error)
+	}
+
+	// it should flip readiness when draining
+	server.localDraining = 1
+
+	report = server.Healthz(context.Background(), true)
+	it.Equal(HealthStatusDraining, report.Status)
+}
+
+func Test_healthCheckWithConcurrent(t *testing.T) {
+	it := assert.New(t)
+
+	var counter int64
+
+	release := make(chan struct{})
+	defer close(release)
+
+	hc := &healthCheck{
+		HealthCheck: HealthCheck{
+			Name: "hung",
+			Checker: HealthCheckerFunc(func(ctx context.Context) error {
+				// the first check hangs until released
+				if atomic.AddInt64(&counter, 1) == 1 {
+					<-release
+				}
+
+				return nil
+			}),
+			Timeout: time.Minute,
+		},
+	}
+
+	go hc.check(context.Background())
+
+	for atomic.LoadInt64(&counter) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// it should not be blocked by the hung check
+	resultc := make(chan *HealthResult, 1)
+	go func() {
+		resultc <- hc.check(context.Background())
+	}()
+
+	select {
+	case result := <-resultc:
+		it.Equal(HealthStatusOK, result.Status)
+
+	case <-time.After(time.Second):
+		it.Fail("it should not be blocked by the hung check")
+	}
+}
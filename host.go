@@ -0,0 +1,216 @@
+package gogo
+
+import (
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// virtualHosts defines routes trees of server by Host header, it resolves
+// exact names before wildcards, and the longest wildcard wins.
+type virtualHosts struct {
+	mux       sync.RWMutex
+	exact     map[string]*AppGroup
+	wildcards []*AppGroup // sorted by length of suffix, the longest first
+}
+
+// get returns group registered with pattern, it returns nil if not found.
+func (vh *virtualHosts) get(pattern string) *AppGroup {
+	vh.mux.RLock()
+	defer vh.mux.RUnlock()
+
+	if !strings.HasPrefix(pattern, "*.") {
+		return vh.exact[pattern]
+	}
+
+	for _, group := range vh.wildcards {
+		if group.host == pattern {
+			return group
+		}
+	}
+
+	return nil
+}
+
+// add registers group for its host, it returns the registered one if exists.
+func (vh *virtualHosts) add(group *AppGroup) *AppGroup {
+	vh.mux.Lock()
+	defer vh.mux.Unlock()
+
+	if !strings.HasPrefix(group.host, "*.") {
+		if vh.exact == nil {
+			vh.exact = make(map[string]*AppGroup)
+		}
+
+		if registered, ok := vh.exact[group.host]; ok {
+			return registered
+		}
+
+		vh.exact[group.host] = group
+		return group
+	}
+
+	for _, registered := range vh.wildcards {
+		if registered.host == group.host {
+			return registered
+		}
+	}
+
+	vh.wildcards = append(vh.wildcards, group)
+
+	sort.SliceStable(vh.wildcards, func(i, j int) bool {
+		return len(vh.wildcards[i].host) > len(vh.wildcards[j].host)
+	})
+
+	return group
+}
+
+// match returns group of host resolved, it returns nil if there is no matched.
+func (vh *virtualHosts) match(host string) *AppGroup {
+	vh.mux.RLock()
+	defer vh.mux.RUnlock()
+
+	if len(vh.exact) == 0 && len(vh.wildcards) == 0 {
+		return nil
+	}
+
+	host = normalizeHost(host)
+	if host == "" {
+		return nil
+	}
+
+	if group, ok := vh.exact[host]; ok {
+		return group
+	}
+
+	for _, group := range vh.wildcards {
+		// NOTE: *.example.com matches sub domains only, excluding example.com
+		suffix := group.host[1:]
+		if len(host) > len(suffix) && strings.HasSuffix(host, suffix) {
+			return group
+		}
+	}
+
+	return nil
+}
+
+// each calls fn with group of all hosts registered
+func (vh *virtualHosts) each(fn func(group *AppGroup)) {
+	vh.mux.RLock()
+	groups := make([]*AppGroup, 0, len(vh.exact)+len(vh.wildcards))
+	for _, group := range vh.exact {
+		groups = append(groups, group)
+	}
+	groups = append(groups, vh.wildcards...)
+	vh.mux.RUnlock()
+
+	for _, group := range groups {
+		fn(group)
+	}
+}
+
+// normalizeHost returns host without port and trailing dot in lower case
+func normalizeHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	host = strings.TrimPrefix(host, "[")
+	host = strings.TrimSuffix(host, "]")
+	host = strings.TrimSuffix(host, ".")
+
+	return strings.ToLower(host)
+}
+
+// normalizeHostPattern returns pattern normalized, it returns empty if pattern
+// is invalid. Wildcard is only allowed as the leftmost label, e.g. *.example.com.
+func normalizeHostPattern(pattern string) string {
+	pattern = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(pattern), "."))
+
+	name := strings.TrimPrefix(pattern, "*.")
+	if name == "" || strings.ContainsAny(name, "*/:") {
+		return ""
+	}
+
+	return pattern
+}
+
+// Host returns routes tree for requests with Host header matched pattern, it
+// creates a new one with its own NotFound and MethodNotAllowed handlers if not
+// registered. The pattern is an exact name, e.g. api.example.com, or a wildcard
+// of sub domains, e.g. *.example.com. Requests matched no host are served by
+// routes of server, which is the default host.
+//
+// A new routes tree inherits filters, timeout and route options of server like
+// NewGroup, and names its routes within the host, see HostURLFor. It inherits
+// auto options and CORS of server as well, see SetAutoOptions.
+//
+// NOTE: Internal routes, e.g. /-/healthz, are always served by the default host.
+func (s *AppServer) Host(pattern string, filters ...Middleware) Grouper {
+	host := normalizeHostPattern(pattern)
+	if host == "" {
+		panic(ErrInvalidHost)
+	}
+
+	if group := s.localHosts.get(host); group != nil {
+		if len(filters) > 0 {
+			group.Use(filters...)
+		}
+
+		return group
+	}
+
+	s.AppGroup.mux.Lock()
+	group := NewAppGroup("/", s)
+	group.host = host
+	group.filters = s.AppGroup.buildMiddlewares(filters...)
+	group.timeout = s.AppGroup.timeout
+	group.options = s.AppGroup.options
+	s.AppGroup.mux.Unlock()
+
+	registered := s.localHosts.add(group)
+	if registered != group {
+		return registered
+	}
+
+	// NOTE: it's after adding for hosts registered during prepare
+	if auto, cors := s.AppGroup.tree.autoOptionsPolicy(); auto {
+		group.setAutoOptions(cors)
+	}
+
+	return group
+}
+
+// Hosts returns patterns of all hosts registered, exact names first.
+func (s *AppServer) Hosts() []string {
+	s.localHosts.mux.RLock()
+	defer s.localHosts.mux.RUnlock()
+
+	hosts := make([]string, 0, len(s.localHosts.exact)+len(s.localHosts.wildcards))
+	for host := range s.localHosts.exact {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	for _, group := range s.localHosts.wildcards {
+		hosts = append(hosts, group.host)
+	}
+
+	return hosts
+}
+
+// lookupGroup returns group of routes tree resolved by Host header of request,
+// it returns the group itself if there is no host matched.
+func (r *AppGroup) lookupGroup(req *http.Request) *AppGroup {
+	if isReservedRoute(req.URL.Path) {
+		return r.server.AppGroup
+	}
+
+	if group := r.server.localHosts.match(req.Host); group != nil {
+		return group
+	}
+
+	return r
+}
@@ -0,0 +1,235 @@
+package gogo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golib/assert"
+)
+
+func Test_ServerHost(t *testing.T) {
+	it := assert.New(t)
+	server := fakeServer()
+
+	server.GET("/hosts", func(ctx *Context) {
+		ctx.Text("default")
+	})
+
+	api := server.Host("API.example.com")
+	api.GET("/hosts", func(ctx *Context) {
+		ctx.Text("api")
+	})
+	it.Equal(api, server.Host("api.example.com."))
+
+	wildcard := server.Host("*.example.com")
+	wildcard.GET("/hosts", func(ctx *Context) {
+		ctx.Text("wildcard")
+	})
+
+	deeper := server.Host("*.v2.example.com")
+	deeper.GET("/hosts", func(ctx *Context) {
+		ctx.Text("deeper")
+	})
+
+	it.Equal([]string{"api.example.com", "*.v2.example.com", "*.example.com"}, server.Hosts())
+
+	testCases := map[string]string{
+		"api.example.com":      "api",
+		"API.Example.com:8080": "api",
+		"www.example.com":      "wildcard",
+		"a.b.example.com":      "wildcard",
+		"www.v2.example.com":   "deeper",
+		"example.com":          "default",
+		"127.0.0.1:9090":       "default",
+		"[::1]:9090":           "default",
+		"":                     "default",
+	}
+	for host, expected := range testCases {
+		r, _ := http.NewRequest(http.MethodGet, "/hosts", nil)
+		r.Host = host
+		w := httptest.NewRecorder()
+
+		server.ServeHTTP(w, r)
+		it.Equal(http.StatusOK, w.Code, host)
+		it.Equal(expected, w.Body.String(), host)
+	}
+}
+
+func Test_ServerHostWithInvalid(t *testing.T) {
+	it := assert.New(t)
+	server := fakeServer()
+
+	for _, pattern := range []string{"", "*", "*.", "a.*.example.com", "example.com:8080", "example.com/v1"} {
+		it.Panics(func() {
+			server.Host(pattern)
+		}, pattern)
+	}
+}
+
+func Test_ServerHostWithNotFound(t *testing.T) {
+	it := assert.New(t)
+	server := fakeServer()
+
+	server.GET("/hosts", func(ctx *Context) {
+		ctx.Text("default")
+	})
+
+	api := server.Host("api.example.com")
+	api.GET("/hosts", func(ctx *Context) {
+		ctx.Text("api")
+	})
+	api.SetNotFound(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("api not found"))
+	}))
+	api.SetMethodNotAllowed(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		w.Write([]byte("api method not allowed"))
+	}))
+
+	// not found
+	r, _ := http.NewRequest(http.MethodGet, "/missing", nil)
+	r.Host = "api.example.com"
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusNotFound, w.Code)
+	it.Equal("api not found", w.Body.String())
+
+	// method not allowed
+	r, _ = http.NewRequest(http.MethodPost, "/hosts", nil)
+	r.Host = "api.example.com"
+	w = httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusMethodNotAllowed, w.Code)
+	it.Equal("api method not allowed", w.Body.String())
+
+	// it should not change default host
+	r, _ = http.NewRequest(http.MethodPost, "/hosts", nil)
+	w = httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusNotFound, w.Code)
+	it.Contains(w.Body.String(), "not found")
+	it.NotContains(w.Body.String(), "api")
+}
+
+func Test_ServerHostWithInternalRoutes(t *testing.T) {
+	it := assert.New(t)
+	logger := NewAppLogger("nil", "")
+	config, _ := fakeConfig("application.routes.yml")
+
+	server := NewAppServer(config, logger)
+	server.Host("api.example.com").GET("/hosts", fakePackageAction)
+	server.Host("api.example.com").GET("/-/custom", func(ctx *Context) {
+		ctx.Text("custom")
+	})
+	server.prepare()
+
+	r, _ := http.NewRequest(http.MethodGet, GogoRoutes, nil)
+	r.Host = "api.example.com"
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusOK, w.Code)
+	it.Contains(w.Body.String(), `"host":"api.example.com"`)
+
+	// it should serve other routes with prefix by host
+	r, _ = http.NewRequest(http.MethodGet, "/-/custom", nil)
+	r.Host = "api.example.com"
+	w = httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusOK, w.Code)
+	it.Equal("custom", w.Body.String())
+
+	routes := server.Routes()
+	if it.Len(routes, 2) {
+		it.Equal("api.example.com", routes[1].Host)
+		it.Equal("/hosts", routes[1].Path)
+	}
+}
+
+func Test_ServerHostWithFilters(t *testing.T) {
+	it := assert.New(t)
+	server := fakeServer()
+
+	server.Use(func(ctx *Context) {
+		ctx.AddHeader("X-Filters", "server")
+		ctx.Next()
+	})
+
+	api := server.Host("api.example.com", func(ctx *Context) {
+		ctx.AddHeader("X-Filters", "host")
+		ctx.Next()
+	})
+	api.GET("/hosts", func(ctx *Context) {
+		ctx.Text("api")
+	})
+
+	r, _ := http.NewRequest(http.MethodGet, "/hosts", nil)
+	r.Host = "api.example.com"
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusOK, w.Code)
+	it.Equal("api", w.Body.String())
+	it.Equal([]string{"server", "host"}, w.Header()["X-Filters"])
+
+	routes := server.Routes()
+	if it.Len(routes, 1) {
+		it.Equal(2, routes[0].Middlewares)
+	}
+}
+
+func Test_ServerHostWithRouteNames(t *testing.T) {
+	it := assert.New(t)
+	server := fakeServer()
+
+	server.GET("/users/:user", fakePackageAction, RouteName("users.show"))
+	server.GET("/about", fakePackageAction, RouteName("about"))
+
+	api := server.Host("api.example.com")
+	it.NotPanics(func() {
+		api.GET("/v1/users/:user", func(ctx *Context) {
+			uri, _ := ctx.URLFor("users.show", 1)
+			about, _ := ctx.URLFor("about")
+
+			ctx.Text(uri + " " + about)
+		}, RouteName("users.show"))
+	})
+
+	uri, err := server.URLFor("users.show", 1)
+	if it.Nil(err) {
+		it.Equal("/users/1", uri)
+	}
+
+	uri, err = server.HostURLFor("API.example.com", "users.show", 1)
+	if it.Nil(err) {
+		it.Equal("/v1/users/1", uri)
+	}
+
+	// it should fall back to the default host
+	uri, err = server.HostURLFor("api.example.com", "about")
+	if it.Nil(err) {
+		it.Equal("/about", uri)
+	}
+
+	_, err = server.HostURLFor("www.example.com", "unknown")
+	it.Equal(ErrRouteNotNamed, err)
+
+	// it should resolve names within host of the current route
+	r, _ := http.NewRequest(http.MethodGet, "/v1/users/2", nil)
+	r.Host = "api.example.com"
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal("/v1/users/1 /about", w.Body.String())
+
+	// it should panic with another path of the same name within host
+	it.Panics(func() {
+		api.GET("/v2/users/:user", fakePackageAction, RouteName("users.show"))
+	})
+}
@@ -0,0 +1,181 @@
+package listeners
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+)
+
+// errors
+var (
+	ErrNoCertificates = errors.New("no certificates")
+)
+
+// A KeyPair defines files of a public/private key pair
+type KeyPair struct {
+	CertFile string
+	KeyFile  string
+}
+
+// Certificates manages tls certificates of key pairs which are selected by SNI.
+// It's safe to reload certificates at fly.
+type Certificates struct {
+	pairs []KeyPair
+
+	mux   sync.RWMutex
+	certs []*tls.Certificate
+	names map[string]*tls.Certificate
+}
+
+// NewCertificates loads all key pairs and returns *Certificates.
+func NewCertificates(pairs ...KeyPair) (*Certificates, error) {
+	certs := &Certificates{
+		pairs: pairs,
+	}
+
+	err := certs.Reload()
+	if err != nil {
+		return nil, err
+	}
+
+	return certs, nil
+}
+
+// Files returns all files of key pairs
+func (c *Certificates) Files() []string {
+	files := make([]string, 0, len(c.pairs)*2)
+	for _, pair := range c.pairs {
+		files = append(files, pair.CertFile, pair.KeyFile)
+	}
+
+	return files
+}
+
+// Reload reloads all key pairs, it keeps certificates loaded before if any error occurred.
+func (c *Certificates) Reload() error {
+	if len(c.pairs) == 0 {
+		return ErrNoCertificates
+	}
+
+	certs := make([]*tls.Certificate, 0, len(c.pairs))
+	names := make(map[string]*tls.Certificate)
+
+	for _, pair := range c.pairs {
+		cert, err := tls.LoadX509KeyPair(pair.CertFile, pair.KeyFile)
+		if err != nil {
+			return err
+		}
+
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			return err
+		}
+		cert.Leaf = leaf
+
+		// NOTE: the first key pair takes precedence of the same name
+		for _, name := range certificateNames(leaf) {
+			if _, ok := names[name]; !ok {
+				names[name] = &cert
+			}
+		}
+
+		certs = append(certs, &cert)
+	}
+
+	c.mux.Lock()
+	c.certs = certs
+	c.names = names
+	c.mux.Unlock()
+
+	return nil
+}
+
+// GetCertificate returns certificate matched server name of client hello,
+// it returns the first certificate if there is no matched.
+//
+// NOTE: It's used for tls.Config.GetCertificate.
+func (c *Certificates) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	if len(c.certs) == 0 {
+		return nil, ErrNoCertificates
+	}
+
+	name := strings.ToLower(strings.TrimSuffix(hello.ServerName, "."))
+	if name != "" {
+		if cert, ok := c.names[name]; ok {
+			return cert, nil
+		}
+
+		// try wildcard, e.g. *.example.com for www.example.com
+		if i := strings.Index(name, "."); i > 0 {
+			if cert, ok := c.names["*"+name[i:]]; ok {
+				return cert, nil
+			}
+		}
+	}
+
+	return c.certs[0], nil
+}
+
+// TLSConfig returns *tls.Config with certificates
+func (c *Certificates) TLSConfig() *tls.Config {
+	return &tls.Config{
+		GetCertificate: c.GetCertificate,
+	}
+}
+
+func certificateNames(leaf *x509.Certificate) (names []string) {
+	if leaf.Subject.CommonName != "" {
+		names = append(names, strings.ToLower(leaf.Subject.CommonName))
+	}
+
+	for _, name := range leaf.DNSNames {
+		names = append(names, strings.ToLower(name))
+	}
+
+	return
+}
+
+// NewClientCAs returns *x509.CertPool of pem encoded ca bundle file
+func NewClientCAs(filename string) (*x509.CertPool, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in %s", filename)
+	}
+
+	return pool, nil
+}
+
+// ParseClientAuth returns tls.ClientAuthType of mode, valid values are none,
+// request, require and verify_if_given. It returns tls.NoClientCert for empty.
+//
+// NOTE: Certificates of request mode are never verified.
+func ParseClientAuth(mode string) (tls.ClientAuthType, error) {
+	switch strings.Replace(strings.ToLower(mode), "-", "_", -1) {
+	case "", "none":
+		return tls.NoClientCert, nil
+
+	case "request":
+		return tls.RequestClientCert, nil
+
+	case "require":
+		return tls.RequireAndVerifyClientCert, nil
+
+	case "verify_if_given":
+		return tls.VerifyClientCertIfGiven, nil
+
+	}
+
+	return tls.NoClientCert, fmt.Errorf("invalid client auth %q, available values are none, request, require and verify_if_given", mode)
+}
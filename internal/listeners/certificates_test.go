@@ -0,0 +1,129 @@
+package listeners
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/golib/assert"
+)
+
+var (
+	fakeKeyPair = func(name string) KeyPair {
+		root, _ := os.Getwd()
+
+		return KeyPair{
+			CertFile: path.Join(root, "..", "..", "testdata", "ssl", name+".crt"),
+			KeyFile:  path.Join(root, "..", "..", "testdata", "ssl", name+".key"),
+		}
+	}
+)
+
+func Test_Certificates(t *testing.T) {
+	it := assert.New(t)
+
+	certs, err := NewCertificates(fakeKeyPair("a.example.com"), fakeKeyPair("b.example.com"))
+	if !it.Nil(err) {
+		return
+	}
+	it.Len(certs.Files(), 4)
+
+	testCases := map[string]string{
+		"":                  "a.example.com",
+		"a.example.com":     "a.example.com",
+		"A.Example.Com.":    "a.example.com",
+		"www.b.example.com": "*.b.example.com",
+		"unknown.com":       "a.example.com",
+	}
+
+	for name, expected := range testCases {
+		cert, err := certs.GetCertificate(&tls.ClientHelloInfo{
+			ServerName: name,
+		})
+		if it.Nil(err) {
+			it.Equal(expected, cert.Leaf.Subject.CommonName)
+		}
+	}
+}
+
+func Test_CertificatesWithReload(t *testing.T) {
+	it := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "gogo-certificates")
+	if !it.Nil(err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	pair := KeyPair{
+		CertFile: path.Join(dir, "server.crt"),
+		KeyFile:  path.Join(dir, "server.key"),
+	}
+	copyKeyPair := func(src KeyPair) {
+		data, _ := ioutil.ReadFile(src.CertFile)
+		ioutil.WriteFile(pair.CertFile, data, 0644)
+
+		data, _ = ioutil.ReadFile(src.KeyFile)
+		ioutil.WriteFile(pair.KeyFile, data, 0600)
+	}
+
+	copyKeyPair(fakeKeyPair("a.example.com"))
+
+	certs, err := NewCertificates(pair)
+	if !it.Nil(err) {
+		return
+	}
+
+	hello := &tls.ClientHelloInfo{}
+
+	cert, _ := certs.GetCertificate(hello)
+	it.Equal("a.example.com", cert.Leaf.Subject.CommonName)
+
+	// it should reload new certificate
+	copyKeyPair(fakeKeyPair("b.example.com"))
+
+	if it.Nil(certs.Reload()) {
+		cert, _ = certs.GetCertificate(hello)
+		it.Equal("*.b.example.com", cert.Leaf.Subject.CommonName)
+	}
+
+	// it should keep the old for failure
+	ioutil.WriteFile(pair.CertFile, []byte("invalid"), 0644)
+
+	if it.NotNil(certs.Reload()) {
+		cert, _ = certs.GetCertificate(hello)
+		it.Equal("*.b.example.com", cert.Leaf.Subject.CommonName)
+	}
+}
+
+func Test_CertificatesWithoutKeyPair(t *testing.T) {
+	it := assert.New(t)
+
+	_, err := NewCertificates()
+	it.Equal(ErrNoCertificates, err)
+}
+
+func Test_ParseClientAuth(t *testing.T) {
+	it := assert.New(t)
+
+	testCases := map[string]tls.ClientAuthType{
+		"":                tls.NoClientCert,
+		"none":            tls.NoClientCert,
+		"request":         tls.RequestClientCert,
+		"require":         tls.RequireAndVerifyClientCert,
+		"verify_if_given": tls.VerifyClientCertIfGiven,
+		"Verify-If-Given": tls.VerifyClientCertIfGiven,
+	}
+
+	for mode, expected := range testCases {
+		auth, err := ParseClientAuth(mode)
+		if it.Nil(err, mode) {
+			it.Equal(expected, auth, mode)
+		}
+	}
+
+	_, err := ParseClientAuth("unknown")
+	it.NotNil(err)
+}
@@ -0,0 +1,155 @@
+package listeners
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// env names for handing listeners to child process
+const (
+	EnvListenerFds = "GOGO_LISTENER_FDS"
+	EnvReadyFd     = "GOGO_READY_FD"
+)
+
+var (
+	// listenFdsStart is the first fd of inherited files, 0, 1 and 2 are
+	// reserved for stdin, stdout and stderr.
+	listenFdsStart = 3
+
+	inheritOnce  sync.Once
+	inheritMux   sync.Mutex
+	inheritFiles map[string]*os.File
+)
+
+// A filer represents listener which can be duplicated as *os.File
+type filer interface {
+	File() (*os.File, error)
+}
+
+// inherited returns a *os.File inherited from parent process for network and address,
+// it returns nil if there is no matched file.
+//
+// NOTE: The returned file is removed from inherited, so it can only be adopted once.
+func inherited(network, address string) *os.File {
+	inheritOnce.Do(parseEnviron)
+
+	inheritMux.Lock()
+	defer inheritMux.Unlock()
+
+	key := inheritKey(network, address)
+
+	file, ok := inheritFiles[key]
+	if ok {
+		delete(inheritFiles, key)
+	}
+
+	return file
+}
+
+// parseEnviron resolves files inherited from parent process and listeners
+// passed by systemd, then cleans env for child processes.
+func parseEnviron() {
+	inheritFiles = parseInherited(os.Getenv(EnvListenerFds))
+	activatedListeners = parseActivated(os.Getenv(EnvSdListenPid), os.Getenv(EnvSdListenFds))
+
+	for _, env := range []string{EnvListenerFds, EnvSdListenPid, EnvSdListenFds, EnvSdListenFdNames} {
+		os.Unsetenv(env)
+	}
+}
+
+// parseInherited resolves files from value of EnvListenerFds, which is formated
+// in network://address separated by semicolon, e.g. tcp://:9090;unix:///tmp/gogo.sock
+func parseInherited(value string) map[string]*os.File {
+	files := make(map[string]*os.File)
+	if value == "" {
+		return files
+	}
+
+	for i, key := range strings.Split(value, ";") {
+		if key == "" {
+			continue
+		}
+
+		fd := listenFdsStart + i
+
+		files[key] = os.NewFile(uintptr(fd), key)
+	}
+
+	return files
+}
+
+func inheritKey(network, address string) string {
+	return network + "://" + address
+}
+
+// Export returns env and files of listeners for handing them to a child process.
+// The child process should be started with files as extra files in order, and
+// the env appended.
+//
+// NOTE: It's the caller's responsibility to close all files returned.
+func Export(ifaces ...Interface) (env []string, files []*os.File, err error) {
+	var keys []string
+
+	for _, iface := range ifaces {
+		var file *os.File
+
+		file, err = iface.File()
+		if err != nil {
+			for _, file := range files {
+				file.Close()
+			}
+
+			return nil, nil, err
+		}
+
+		keys = append(keys, inheritKey(iface.Network(), iface.Address()))
+		files = append(files, file)
+	}
+
+	env = []string{
+		EnvListenerFds + "=" + strings.Join(keys, ";"),
+	}
+
+	return
+}
+
+// ReadyEnv returns env of ready fd for child process with count of extra files
+// exported before.
+func ReadyEnv(n int) string {
+	return EnvReadyFd + "=" + strconv.Itoa(listenFdsStart+n)
+}
+
+// NotifyReady notifies parent process that all listeners have been adopted
+// and server is ready for serving. It does nothing if the current process is
+// not started by an upgrade.
+func NotifyReady() error {
+	value := os.Getenv(EnvReadyFd)
+	if value == "" {
+		return nil
+	}
+
+	os.Unsetenv(EnvReadyFd)
+
+	fd, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s=%s: %v", EnvReadyFd, value, err)
+	}
+
+	file := os.NewFile(uintptr(fd), EnvReadyFd)
+	defer file.Close()
+
+	_, err = file.Write([]byte{'1'})
+	return err
+}
+
+// fileListener returns net.Listener of inherited file, it always closes the file
+// since net.FileListener duplicates it.
+func fileListener(file *os.File) (net.Listener, error) {
+	defer file.Close()
+
+	return net.FileListener(file)
+}
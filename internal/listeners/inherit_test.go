@@ -0,0 +1,70 @@
+package listeners
+
+import (
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/golib/assert"
+)
+
+func Test_ExportWithInherited(t *testing.T) {
+	it := assert.New(t)
+
+	parent := New(false)
+
+	conn, err := parent.Listen("tcp", "127.0.0.1:0")
+	if !it.Nil(err) {
+		return
+	}
+	defer parent.Shutdown()
+
+	env, files, err := Export(parent)
+	if it.Nil(err) && it.Len(files, 1) {
+		it.Equal([]string{EnvListenerFds + "=tcp://127.0.0.1:0"}, env)
+	}
+
+	// simulate child process
+	listenFdsStart = int(files[0].Fd())
+	inheritOnce = sync.Once{}
+	defer func() {
+		listenFdsStart = 3
+		inheritOnce = sync.Once{}
+	}()
+
+	os.Setenv(EnvListenerFds, strings.TrimPrefix(env[0], EnvListenerFds+"="))
+
+	child := New(false)
+
+	iconn, err := child.Listen("tcp", "127.0.0.1:0")
+	if it.Nil(err) {
+		defer child.Shutdown()
+
+		it.True(child.Inherited())
+		it.Equal(conn.Addr().String(), iconn.Addr().String())
+
+		// it should accept connection of parent address
+		go func() {
+			c, err := net.Dial("tcp", conn.Addr().String())
+			if err == nil {
+				c.Close()
+			}
+		}()
+
+		c, err := iconn.Accept()
+		if it.Nil(err) {
+			c.Close()
+		}
+	}
+
+	// it should clean env
+	it.Empty(os.Getenv(EnvListenerFds))
+}
+
+func Test_ReadyEnv(t *testing.T) {
+	it := assert.New(t)
+
+	it.Equal(EnvReadyFd+"=5", ReadyEnv(2))
+}
@@ -0,0 +1,175 @@
+package listeners
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// A Stats defines counters of connections for listener
+type Stats struct {
+	Accepted int64 `json:"accepted"` // total of connections accepted
+	Rejected int64 `json:"rejected"` // total of connections rejected by limit of per remote IP
+	Active   int64 `json:"active"`   // connections not closed yet
+}
+
+// limitListener wraps net.Listener with counting and limiting connections.
+// It stops accepting connections when max connections reached, so clients
+// are queued by backlog of the kernel, and closes connections exceeded limit
+// of per remote IP immediately.
+type limitListener struct {
+	net.Listener
+	sem      chan struct{} // nil for unlimited
+	maxPerIP int           // 0 for unlimited
+
+	once   sync.Once
+	closed chan struct{}
+
+	mux sync.Mutex
+	ips map[string]int
+
+	accepted int64
+	rejected int64
+	active   int64
+}
+
+func newLimitListener(ln net.Listener, maxConns, maxConnsPerIP int) *limitListener {
+	ll := &limitListener{
+		Listener: ln,
+		maxPerIP: maxConnsPerIP,
+		closed:   make(chan struct{}),
+		ips:      make(map[string]int),
+	}
+
+	if maxConns > 0 {
+		ll.sem = make(chan struct{}, maxConns)
+	}
+
+	return ll
+}
+
+// Accept waits for a free slot and returns the next connection.
+func (ll *limitListener) Accept() (net.Conn, error) {
+	for {
+		if ll.sem != nil {
+			select {
+			case ll.sem <- struct{}{}:
+			case <-ll.closed:
+				return nil, fmt.Errorf("accept %s: use of closed network connection", ll.Addr())
+			}
+		}
+
+		conn, err := ll.Listener.Accept()
+		if err != nil {
+			ll.release()
+			return nil, err
+		}
+
+		ip := remoteIP(conn.RemoteAddr())
+		if !ll.acquireIP(ip) {
+			atomic.AddInt64(&ll.rejected, 1)
+
+			conn.Close()
+			ll.release()
+			continue
+		}
+
+		atomic.AddInt64(&ll.accepted, 1)
+		atomic.AddInt64(&ll.active, 1)
+
+		return &limitConn{
+			Conn:     conn,
+			listener: ll,
+			ip:       ip,
+		}, nil
+	}
+}
+
+// Close closes the underlying listener and unblocks Accept waiting for slot.
+func (ll *limitListener) Close() error {
+	ll.once.Do(func() {
+		close(ll.closed)
+	})
+
+	return ll.Listener.Close()
+}
+
+// Stats returns counters of the listener
+func (ll *limitListener) Stats() Stats {
+	return Stats{
+		Accepted: atomic.LoadInt64(&ll.accepted),
+		Rejected: atomic.LoadInt64(&ll.rejected),
+		Active:   atomic.LoadInt64(&ll.active),
+	}
+}
+
+func (ll *limitListener) release() {
+	if ll.sem != nil {
+		<-ll.sem
+	}
+}
+
+func (ll *limitListener) acquireIP(ip string) bool {
+	if ll.maxPerIP <= 0 || ip == "" {
+		return true
+	}
+
+	ll.mux.Lock()
+	defer ll.mux.Unlock()
+
+	if ll.ips[ip] >= ll.maxPerIP {
+		return false
+	}
+
+	ll.ips[ip]++
+	return true
+}
+
+func (ll *limitListener) releaseIP(ip string) {
+	if ll.maxPerIP <= 0 || ip == "" {
+		return
+	}
+
+	ll.mux.Lock()
+	defer ll.mux.Unlock()
+
+	ll.ips[ip]--
+	if ll.ips[ip] <= 0 {
+		delete(ll.ips, ip)
+	}
+}
+
+// limitConn releases slot of listener when closed
+type limitConn struct {
+	net.Conn
+	listener *limitListener
+	ip       string
+	once     sync.Once
+}
+
+func (lc *limitConn) Close() error {
+	err := lc.Conn.Close()
+
+	lc.once.Do(func() {
+		atomic.AddInt64(&lc.listener.active, -1)
+
+		lc.listener.releaseIP(lc.ip)
+		lc.listener.release()
+	})
+
+	return err
+}
+
+// remoteIP returns IP of addr, it returns empty for non-IP networks, e.g. unix.
+func remoteIP(addr net.Addr) string {
+	switch v := addr.(type) {
+	case *net.TCPAddr:
+		return v.IP.String()
+
+	case *net.UDPAddr:
+		return v.IP.String()
+	}
+
+	return ""
+}
@@ -0,0 +1,151 @@
+package listeners
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golib/assert"
+)
+
+func Test_ListenerWithLimits(t *testing.T) {
+	it := assert.New(t)
+
+	listener := New(false).WithLimits(1, 0)
+
+	conn, err := listener.Listen("tcp", "127.0.0.1:0")
+	if !it.Nil(err) {
+		return
+	}
+	defer conn.Close()
+
+	for i := 0; i < 2; i++ {
+		go func() {
+			c, err := net.Dial("tcp", conn.Addr().String())
+			if err == nil {
+				time.Sleep(300 * time.Millisecond)
+				c.Close()
+			}
+		}()
+	}
+
+	first, err := conn.Accept()
+	if !it.Nil(err) {
+		return
+	}
+
+	// it should stop accepting when max conns reached
+	acceptc := make(chan net.Conn, 1)
+	go func() {
+		c, err := conn.Accept()
+		if err == nil {
+			acceptc <- c
+		}
+	}()
+
+	select {
+	case <-acceptc:
+		it.Fail("it should not accept more than max conns")
+
+	case <-time.After(100 * time.Millisecond):
+	}
+	it.Equal(Stats{Accepted: 1, Active: 1}, listener.Stats())
+
+	// it should accept after closed
+	first.Close()
+
+	select {
+	case c := <-acceptc:
+		c.Close()
+
+	case <-time.After(time.Second):
+		it.Fail("it should accept after slot released")
+	}
+	it.Equal(Stats{Accepted: 2, Active: 0}, listener.Stats())
+}
+
+func Test_ListenerWithLimitsPerIP(t *testing.T) {
+	it := assert.New(t)
+
+	listener := New(false).WithLimits(0, 1)
+
+	conn, err := listener.Listen("tcp", "127.0.0.1:0")
+	if !it.Nil(err) {
+		return
+	}
+	defer conn.Close()
+
+	clients := make([]net.Conn, 2)
+	for i := range clients {
+		clients[i], err = net.Dial("tcp", conn.Addr().String())
+		if !it.Nil(err) {
+			return
+		}
+		defer clients[i].Close()
+	}
+
+	first, err := conn.Accept()
+	if !it.Nil(err) {
+		return
+	}
+	defer first.Close()
+
+	// it should close connection exceeded immediately
+	go conn.Accept()
+
+	clients[1].SetReadDeadline(time.Now().Add(time.Second))
+
+	_, err = clients[1].Read(make([]byte, 1))
+	if it.NotNil(err) {
+		it.Equal(Stats{Accepted: 1, Rejected: 1, Active: 1}, listener.Stats())
+	}
+}
+
+func Test_ListenerWithLimitsAndProxyProtocol(t *testing.T) {
+	it := assert.New(t)
+
+	timeout := ProxyHeaderTimeout
+	ProxyHeaderTimeout = 200 * time.Millisecond
+	defer func() {
+		ProxyHeaderTimeout = timeout
+	}()
+
+	trusted, _ := ParseCIDRs([]string{"127.0.0.1"})
+
+	listener := New(false).WithProxyProtocol(trusted).WithLimits(1, 0)
+
+	conn, err := listener.Listen("tcp", "127.0.0.1:0")
+	if !it.Nil(err) {
+		return
+	}
+	defer conn.Close()
+
+	// it should count connections without PROXY header sent
+	silent, err := net.Dial("tcp", conn.Addr().String())
+	if !it.Nil(err) {
+		return
+	}
+	defer silent.Close()
+
+	time.Sleep(50 * time.Millisecond)
+
+	go func() {
+		c, err := net.Dial("tcp", conn.Addr().String())
+		if err == nil {
+			c.Write([]byte("PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\nPING"))
+			time.Sleep(300 * time.Millisecond)
+			c.Close()
+		}
+	}()
+
+	started := time.Now()
+
+	c, err := conn.Accept()
+	if it.Nil(err) {
+		defer c.Close()
+
+		it.True(time.Since(started) >= 100*time.Millisecond)
+		it.Equal("192.168.0.1:56324", c.RemoteAddr().String())
+		it.Equal(Stats{Accepted: 2, Active: 1}, listener.Stats())
+	}
+}
@@ -1,34 +1,49 @@
 package listeners
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"os"
 	"strings"
 	"sync"
 
 	"github.com/dolab/gogo/pkgs/hooks"
+	"github.com/dolab/gogo/pkgs/proxyproto"
 )
 
 // A Listener implments Interface
 type Listener struct {
-	http2  bool
-	tlscfg *tls.Config
+	http2   bool
+	tlscfg  *tls.Config
+	proxy   bool
+	trusted []*net.IPNet
 
-	mux     sync.RWMutex
-	network string
-	address string
-	conn    io.Closer
+	maxConns      int
+	maxConnsPerIP int
+
+	mux       sync.RWMutex
+	network   string
+	address   string
+	conn      io.Closer
+	limit     *limitListener
+	inherited bool
 }
 
+// New creates a Listener
 func New(isHTTP2 bool) Interface {
 	return &Listener{
 		http2: isHTTP2,
 	}
 }
 
+// WithTLSConfig sets *tls.Config for the listener, connections accepted will be
+// wrapped with tls.Server if set.
+//
+// NOTE: It must be called before Listen.
 func (l *Listener) WithTLSConfig(cfg *tls.Config) Interface {
 	l.mux.Lock()
 	l.tlscfg = cfg
@@ -37,6 +52,36 @@ func (l *Listener) WithTLSConfig(cfg *tls.Config) Interface {
 	return l
 }
 
+// WithProxyProtocol enables parsing PROXY header of connections accepted from
+// trusted networks, no sources are trusted if there is no networks given.
+// RemoteAddr of connections returns source address of PROXY header.
+//
+// NOTE: It must be called before Listen.
+func (l *Listener) WithProxyProtocol(trusted []*net.IPNet) Interface {
+	l.mux.Lock()
+	l.proxy = true
+	l.trusted = trusted
+	l.mux.Unlock()
+
+	return l
+}
+
+// WithLimits sets max connections in total and per remote IP for the listener,
+// zero means unlimited.
+//
+// Limits are applied to addresses of peers instead of sources of PROXY header,
+// that is, addresses of proxies for PROXY protocol.
+//
+// NOTE: It must be called before Listen.
+func (l *Listener) WithLimits(maxConns, maxConnsPerIP int) Interface {
+	l.mux.Lock()
+	l.maxConns = maxConns
+	l.maxConnsPerIP = maxConnsPerIP
+	l.mux.Unlock()
+
+	return l
+}
+
 // Listen announces on the local network address.
 //
 // The network must be "tcp", "tcp4", "tcp6", "unix" or "unixpacket".
@@ -55,6 +100,9 @@ func (l *Listener) WithTLSConfig(cfg *tls.Config) Interface {
 //
 // See func Dial for a description of the network and address
 // parameters.
+//
+// NOTE: It adopts the listener inherited from parent process or passed by
+// systemd socket activation if exists.
 func (l *Listener) Listen(network, address string) (conn net.Listener, err error) {
 	l.mux.Lock()
 	defer l.mux.Unlock()
@@ -67,9 +115,36 @@ func (l *Listener) Listen(network, address string) (conn net.Listener, err error
 	l.network = network
 	l.address = address
 
-	conn, err = net.Listen(network, address)
-	if err == nil {
-		l.conn = conn
+	if file := inherited(network, address); file != nil {
+		conn, err = fileListener(file)
+		if err == nil {
+			l.inherited = true
+		}
+	} else if ln := activated(network, address); ln != nil {
+		conn = ln
+		l.inherited = true
+	} else {
+		conn, err = net.Listen(network, address)
+	}
+	if err != nil {
+		return
+	}
+
+	l.conn = conn
+
+	// limit and count connections by remote address of peers, PROXY headers are
+	// read within limits for bounding connections which never send it.
+	l.limit = newLimitListener(conn, l.maxConns, l.maxConnsPerIP)
+	conn = l.limit
+
+	// wrap with PROXY protocol if required, it must be parsed before tls handshake
+	if l.proxy {
+		conn = newProxyListener(conn, l.trusted)
+	}
+
+	// wrap with tls if required
+	if l.tlscfg != nil {
+		conn = tls.NewListener(conn, l.tlscfg)
 	}
 	return
 }
@@ -115,6 +190,65 @@ func (l *Listener) ListenPacket(network, address string) (conn net.PacketConn, e
 	return
 }
 
+// Network returns network name of the listener
+func (l *Listener) Network() string {
+	l.mux.RLock()
+	defer l.mux.RUnlock()
+
+	return l.network
+}
+
+// Address returns address of the listener, it's the same as Listen given.
+func (l *Listener) Address() string {
+	l.mux.RLock()
+	defer l.mux.RUnlock()
+
+	return l.address
+}
+
+// Inherited returns true if the listener is adopted from parent process or systemd.
+func (l *Listener) Inherited() bool {
+	l.mux.RLock()
+	defer l.mux.RUnlock()
+
+	return l.inherited
+}
+
+// File returns a copy of the underlying *os.File of listener for handing it to child process.
+//
+// NOTE: unix socket file will not be removed when the listener closed after calling File.
+func (l *Listener) File() (*os.File, error) {
+	l.mux.RLock()
+	defer l.mux.RUnlock()
+
+	if l.conn == nil {
+		return nil, fmt.Errorf("%s:%s is not listening", l.network, l.address)
+	}
+
+	if unix, ok := l.conn.(*net.UnixListener); ok {
+		unix.SetUnlinkOnClose(false)
+	}
+
+	conn, ok := l.conn.(filer)
+	if !ok {
+		return nil, fmt.Errorf("%s:%s can not be exported as file", l.network, l.address)
+	}
+
+	return conn.File()
+}
+
+// Stats returns counters of connections accepted by the listener
+func (l *Listener) Stats() Stats {
+	l.mux.RLock()
+	defer l.mux.RUnlock()
+
+	if l.limit == nil {
+		return Stats{}
+	}
+
+	return l.limit.Stats()
+}
+
 func (l *Listener) Shutdown() {
 	l.mux.Lock()
 	defer l.mux.Unlock()
@@ -130,10 +264,27 @@ func (l *Listener) Shutdown() {
 	return
 }
 
+// ConnContext binds the listener and PROXY header if exists to context of
+// connections accepted, it's used for http.Server.ConnContext.
+func (l *Listener) ConnContext(ctx context.Context, c net.Conn) context.Context {
+	ctx = context.WithValue(ctx, ctxListenerKey, l)
+
+	if header := proxyHeader(c); header != nil {
+		ctx = proxyproto.NewContext(ctx, header)
+	}
+
+	return ctx
+}
+
+// RequestReceivedHook returns hook for requests accepted by the listener only.
 func (l *Listener) RequestReceivedHook() hooks.NamedHook {
 	return hooks.NamedHook{
-		Name: "__listener@default",
+		Name: "__listener@" + l.network + "://" + l.address,
 		Apply: func(w http.ResponseWriter, r *http.Request) bool {
+			if FromContext(r.Context()) != l {
+				return true
+			}
+
 			switch l.network {
 			case "unix":
 				r.URL.Path = strings.TrimPrefix(r.URL.Path, l.address)
@@ -0,0 +1,224 @@
+package listeners
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolab/gogo/pkgs/proxyproto"
+)
+
+// ProxyHeaderTimeout defines max duration for reading PROXY header of connections
+var ProxyHeaderTimeout = 5 * time.Second
+
+// ParseCIDRs returns []*net.IPNet of values given, a plain IP is parsed as
+// a single host network, e.g. 10.0.0.1 is the same as 10.0.0.1/32.
+func ParseCIDRs(values []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(values))
+
+	for _, value := range values {
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP address %q", value)
+			}
+
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+
+			nets = append(nets, &net.IPNet{
+				IP:   ip,
+				Mask: net.CIDRMask(bits, bits),
+			})
+			continue
+		}
+
+		_, ipnet, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, err
+		}
+
+		nets = append(nets, ipnet)
+	}
+
+	return nets, nil
+}
+
+// proxyListener wraps net.Listener with parsing PROXY header of connections
+// accepted from trusted sources. Headers are read in background, so slow
+// clients will not block accepting others.
+//
+// NOTE: PROXY header is optional for trusted sources, and it is never parsed
+// for untrusted sources.
+type proxyListener struct {
+	net.Listener
+	trusted []*net.IPNet
+
+	once      sync.Once
+	closeOnce sync.Once
+	connc     chan net.Conn
+	closed    chan struct{}
+	done      chan struct{}
+	err       error
+}
+
+func newProxyListener(ln net.Listener, trusted []*net.IPNet) *proxyListener {
+	return &proxyListener{
+		Listener: ln,
+		trusted:  trusted,
+		connc:    make(chan net.Conn),
+		closed:   make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Accept waits for and returns the next connection with PROXY header parsed.
+func (pl *proxyListener) Accept() (net.Conn, error) {
+	pl.once.Do(func() {
+		go pl.accept()
+	})
+
+	select {
+	case conn := <-pl.connc:
+		return conn, nil
+
+	case <-pl.done:
+		return nil, pl.err
+
+	case <-pl.closed:
+		return nil, fmt.Errorf("accept %s: use of closed network connection", pl.Addr())
+	}
+}
+
+// Close closes the underlying listener and drops connections not accepted.
+func (pl *proxyListener) Close() error {
+	pl.closeOnce.Do(func() {
+		close(pl.closed)
+	})
+
+	return pl.Listener.Close()
+}
+
+func (pl *proxyListener) accept() {
+	defer close(pl.done)
+
+	for {
+		conn, err := pl.Listener.Accept()
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				time.Sleep(5 * time.Millisecond)
+				continue
+			}
+
+			pl.err = err
+			return
+		}
+
+		if !pl.isTrusted(conn.RemoteAddr()) {
+			pl.deliver(conn)
+			continue
+		}
+
+		go func(conn net.Conn) {
+			pconn, err := newProxyConn(conn)
+			if err != nil {
+				conn.Close()
+				return
+			}
+
+			pl.deliver(pconn)
+		}(conn)
+	}
+}
+
+func (pl *proxyListener) deliver(conn net.Conn) {
+	select {
+	case pl.connc <- conn:
+	case <-pl.closed:
+		conn.Close()
+	}
+}
+
+// isTrusted returns true if addr is in trusted networks, no sources are
+// trusted if there is no trusted networks.
+func (pl *proxyListener) isTrusted(addr net.Addr) bool {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+
+	for _, ipnet := range pl.trusted {
+		if ipnet.Contains(tcpAddr.IP) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// proxyConn overwrites RemoteAddr of net.Conn with source of PROXY header
+type proxyConn struct {
+	net.Conn
+	reader *bufio.Reader
+	header *proxyproto.Header
+}
+
+func newProxyConn(conn net.Conn) (*proxyConn, error) {
+	conn.SetReadDeadline(time.Now().Add(ProxyHeaderTimeout))
+
+	reader := bufio.NewReader(conn)
+
+	header, err := proxyproto.Read(reader)
+	switch err {
+	case nil, proxyproto.ErrNoHeader:
+		// continue
+
+	default:
+		return nil, err
+	}
+
+	conn.SetReadDeadline(time.Time{})
+
+	return &proxyConn{
+		Conn:   conn,
+		reader: reader,
+		header: header,
+	}, nil
+}
+
+// Read reads data buffered when parsing PROXY header first
+func (pc *proxyConn) Read(b []byte) (int, error) {
+	return pc.reader.Read(b)
+}
+
+// RemoteAddr returns source address of PROXY header if exists
+func (pc *proxyConn) RemoteAddr() net.Addr {
+	if pc.header != nil && pc.header.Source != nil {
+		return pc.header.Source
+	}
+
+	return pc.Conn.RemoteAddr()
+}
+
+// proxyHeader returns PROXY header of the connection, it unwraps *tls.Conn.
+func proxyHeader(conn net.Conn) *proxyproto.Header {
+	for {
+		switch c := conn.(type) {
+		case *tls.Conn:
+			conn = c.NetConn()
+
+		case *proxyConn:
+			return c.header
+
+		default:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package listeners
+
+import (
+	"io/ioutil"
+	"net"
+	"testing"
+
+	"github.com/golib/assert"
+)
+
+func Test_ParseCIDRs(t *testing.T) {
+	it := assert.New(t)
+
+	nets, err := ParseCIDRs([]string{"10.0.0.0/8", "192.168.0.1", "::1"})
+	if it.Nil(err) && it.Len(nets, 3) {
+		it.Equal("10.0.0.0/8", nets[0].String())
+		it.Equal("192.168.0.1/32", nets[1].String())
+		it.Equal("::1/128", nets[2].String())
+	}
+
+	_, err = ParseCIDRs([]string{"localhost"})
+	it.NotNil(err)
+
+	_, err = ParseCIDRs([]string{"10.0.0.0/33"})
+	it.NotNil(err)
+}
+
+func Test_ListenerWithProxyProtocol(t *testing.T) {
+	it := assert.New(t)
+
+	trusted, _ := ParseCIDRs([]string{"127.0.0.1"})
+
+	listener := New(false).WithProxyProtocol(trusted)
+
+	conn, err := listener.Listen("tcp", "127.0.0.1:0")
+	if !it.Nil(err) {
+		return
+	}
+	defer conn.Close()
+
+	go func() {
+		c, err := net.Dial("tcp", conn.Addr().String())
+		if err == nil {
+			c.Write([]byte("PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\nPING"))
+			c.Close()
+		}
+	}()
+
+	c, err := conn.Accept()
+	if it.Nil(err) {
+		defer c.Close()
+
+		it.Equal("192.168.0.1:56324", c.RemoteAddr().String())
+		it.NotNil(proxyHeader(c))
+
+		data, _ := ioutil.ReadAll(c)
+		it.Equal("PING", string(data))
+	}
+}
+
+func Test_ListenerWithProxyProtocolUntrusted(t *testing.T) {
+	it := assert.New(t)
+
+	trusted, _ := ParseCIDRs([]string{"10.0.0.0/8"})
+
+	listener := New(false).WithProxyProtocol(trusted)
+
+	conn, err := listener.Listen("tcp", "127.0.0.1:0")
+	if !it.Nil(err) {
+		return
+	}
+	defer conn.Close()
+
+	payload := "PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\n"
+
+	go func() {
+		c, err := net.Dial("tcp", conn.Addr().String())
+		if err == nil {
+			c.Write([]byte(payload))
+			c.Close()
+		}
+	}()
+
+	// it should not parse header of untrusted sources
+	c, err := conn.Accept()
+	if it.Nil(err) {
+		defer c.Close()
+
+		it.Contains(c.RemoteAddr().String(), "127.0.0.1:")
+		it.Nil(proxyHeader(c))
+
+		data, _ := ioutil.ReadAll(c)
+		it.Equal(payload, string(data))
+	}
+}
+
+func Test_ListenerWithProxyProtocolWithoutTrusted(t *testing.T) {
+	it := assert.New(t)
+
+	listener := New(false).WithProxyProtocol(nil)
+
+	conn, err := listener.Listen("tcp", "127.0.0.1:0")
+	if !it.Nil(err) {
+		return
+	}
+	defer conn.Close()
+
+	payload := "PROXY TCP4 127.0.0.1 192.168.0.11 56324 443\r\n"
+
+	go func() {
+		c, err := net.Dial("tcp", conn.Addr().String())
+		if err == nil {
+			c.Write([]byte(payload))
+			c.Close()
+		}
+	}()
+
+	// it should trust nobody without trusted networks
+	c, err := conn.Accept()
+	if it.Nil(err) {
+		defer c.Close()
+
+		it.NotEqual("127.0.0.1:56324", c.RemoteAddr().String())
+		it.Nil(proxyHeader(c))
+
+		data, _ := ioutil.ReadAll(c)
+		it.Equal(payload, string(data))
+	}
+}
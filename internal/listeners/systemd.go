@@ -0,0 +1,158 @@
+package listeners
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// env names of systemd socket activation and notify protocol
+const (
+	EnvSdListenPid     = "LISTEN_PID"
+	EnvSdListenFds     = "LISTEN_FDS"
+	EnvSdListenFdNames = "LISTEN_FDNAMES"
+	EnvSdNotifySocket  = "NOTIFY_SOCKET"
+	EnvSdWatchdogUsec  = "WATCHDOG_USEC"
+	EnvSdWatchdogPid   = "WATCHDOG_PID"
+)
+
+// states of systemd notify protocol
+const (
+	SdReady    = "READY=1"
+	SdStopping = "STOPPING=1"
+	SdWatchdog = "WATCHDOG=1"
+)
+
+var (
+	// sdListenFdsStart is the first fd of sockets passed by systemd
+	sdListenFdsStart = 3
+
+	activatedListeners []net.Listener
+)
+
+// activated returns a listener passed by systemd socket activation which is
+// bound to network and address, it returns nil if there is no matched listener.
+//
+// NOTE: The returned listener is removed from activated, so it can only be adopted once.
+func activated(network, address string) net.Listener {
+	inheritOnce.Do(parseEnviron)
+
+	inheritMux.Lock()
+	defer inheritMux.Unlock()
+
+	for i, ln := range activatedListeners {
+		if !matchAddr(network, address, ln.Addr()) {
+			continue
+		}
+
+		activatedListeners = append(activatedListeners[:i], activatedListeners[i+1:]...)
+
+		return ln
+	}
+
+	return nil
+}
+
+// parseActivated resolves listeners of systemd socket activation, it ignores
+// all if pid is not the current process. Sockets which are not stream, e.g.
+// datagram, are closed.
+func parseActivated(pid, fds string) []net.Listener {
+	if pid == "" || pid != strconv.Itoa(os.Getpid()) {
+		return nil
+	}
+
+	n, err := strconv.Atoi(fds)
+	if err != nil || n < 1 {
+		return nil
+	}
+
+	var lns []net.Listener
+	for i := 0; i < n; i++ {
+		file := os.NewFile(uintptr(sdListenFdsStart+i), EnvSdListenFds)
+
+		ln, err := fileListener(file)
+		if err != nil {
+			continue
+		}
+
+		lns = append(lns, ln)
+	}
+
+	return lns
+}
+
+// matchAddr returns true if addr is the bound address of network and address.
+// The host of tcp address is matched by any of unspecified, equal or both loopback.
+func matchAddr(network, address string, addr net.Addr) bool {
+	switch network {
+	case "unix", "unixpacket":
+		return addr.Network() == network && addr.String() == address
+	}
+
+	laddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+
+	raddr, err := net.ResolveTCPAddr(network, address)
+	if err != nil || raddr.Port != laddr.Port {
+		return false
+	}
+
+	switch {
+	case raddr.IP == nil, raddr.IP.IsUnspecified():
+		return true
+
+	case raddr.IP.Equal(laddr.IP):
+		return true
+
+	case raddr.IP.IsLoopback() && laddr.IP.IsLoopback():
+		return true
+	}
+
+	return false
+}
+
+// SdNotify sends state to systemd by NOTIFY_SOCKET. It does nothing if the
+// current process is not managed by systemd with Type=notify.
+func SdNotify(state string) error {
+	socket := os.Getenv(EnvSdNotifySocket)
+	if socket == "" {
+		return nil
+	}
+
+	// NOTE: socket name prefixed with @ is resolved as abstract by net package
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{
+		Name: socket,
+		Net:  "unixgram",
+	})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(state))
+	return err
+}
+
+// SdWatchdogTimeout returns timeout of systemd watchdog, the service should send
+// SdWatchdog within half of it. It returns zero if watchdog is disabled or
+// it's not for the current process.
+func SdWatchdogTimeout() time.Duration {
+	value := os.Getenv(EnvSdWatchdogUsec)
+	if value == "" {
+		return 0
+	}
+
+	if pid := os.Getenv(EnvSdWatchdogPid); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+
+	usec, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+
+	return time.Duration(usec) * time.Microsecond
+}
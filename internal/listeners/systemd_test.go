@@ -0,0 +1,161 @@
+package listeners
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golib/assert"
+)
+
+func Test_ListenWithActivated(t *testing.T) {
+	it := assert.New(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if !it.Nil(err) {
+		return
+	}
+	defer ln.Close()
+
+	file, err := ln.(*net.TCPListener).File()
+	if !it.Nil(err) {
+		return
+	}
+
+	// simulate systemd socket activation
+	sdListenFdsStart = int(file.Fd())
+	inheritOnce = sync.Once{}
+	defer func() {
+		sdListenFdsStart = 3
+		inheritOnce = sync.Once{}
+		activatedListeners = nil
+	}()
+
+	os.Setenv(EnvSdListenPid, strconv.Itoa(os.Getpid()))
+	os.Setenv(EnvSdListenFds, "1")
+	os.Setenv(EnvSdListenFdNames, "gogo")
+
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	l := New(false)
+
+	conn, err := l.Listen("tcp", "localhost:"+strconv.Itoa(port))
+	if it.Nil(err) {
+		defer l.Shutdown()
+
+		it.True(l.Inherited())
+		it.Equal(ln.Addr().String(), conn.Addr().String())
+
+		// it should accept connection of activated address
+		go func() {
+			c, err := net.Dial("tcp", ln.Addr().String())
+			if err == nil {
+				c.Close()
+			}
+		}()
+
+		c, err := conn.Accept()
+		if it.Nil(err) {
+			c.Close()
+		}
+	}
+
+	// it should clean env
+	it.Empty(os.Getenv(EnvSdListenPid))
+	it.Empty(os.Getenv(EnvSdListenFds))
+	it.Empty(os.Getenv(EnvSdListenFdNames))
+
+	// it should be adopted only once
+	it.Nil(activated("tcp", ln.Addr().String()))
+}
+
+func Test_parseActivatedWithOtherPid(t *testing.T) {
+	it := assert.New(t)
+
+	it.Nil(parseActivated(strconv.Itoa(os.Getpid()+1), "1"))
+	it.Nil(parseActivated("", "1"))
+	it.Nil(parseActivated(strconv.Itoa(os.Getpid()), "invalid"))
+}
+
+func Test_matchAddr(t *testing.T) {
+	it := assert.New(t)
+
+	addr := &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 9090}
+	it.True(matchAddr("tcp", "127.0.0.1:9090", addr))
+	it.True(matchAddr("tcp", "localhost:9090", addr))
+	it.True(matchAddr("tcp", ":9090", addr))
+	it.True(matchAddr("tcp", "0.0.0.0:9090", addr))
+	it.False(matchAddr("tcp", "127.0.0.1:9091", addr))
+	it.False(matchAddr("tcp", "10.0.0.1:9090", addr))
+
+	unix := &net.UnixAddr{Name: "/tmp/gogo.sock", Net: "unix"}
+	it.True(matchAddr("unix", "/tmp/gogo.sock", unix))
+	it.False(matchAddr("unix", "/tmp/other.sock", unix))
+	it.False(matchAddr("tcp", ":9090", unix))
+}
+
+func Test_SdNotify(t *testing.T) {
+	it := assert.New(t)
+
+	// it should do nothing without NOTIFY_SOCKET
+	os.Unsetenv(EnvSdNotifySocket)
+	it.Nil(SdNotify(SdReady))
+
+	dir, err := ioutil.TempDir("", "gogo-systemd")
+	if !it.Nil(err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{path.Join(dir, "notify.sock"), "@gogo-notify-" + strconv.Itoa(os.Getpid())} {
+		conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: name, Net: "unixgram"})
+		if !it.Nil(err) {
+			continue
+		}
+
+		os.Setenv(EnvSdNotifySocket, name)
+
+		it.Nil(SdNotify(SdReady))
+
+		buf := make([]byte, 64)
+
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if it.Nil(err) {
+			it.Equal(SdReady, string(buf[:n]))
+		}
+
+		conn.Close()
+	}
+
+	os.Unsetenv(EnvSdNotifySocket)
+}
+
+func Test_SdWatchdogTimeout(t *testing.T) {
+	it := assert.New(t)
+	defer func() {
+		os.Unsetenv(EnvSdWatchdogUsec)
+		os.Unsetenv(EnvSdWatchdogPid)
+	}()
+
+	it.Zero(SdWatchdogTimeout())
+
+	os.Setenv(EnvSdWatchdogUsec, "3000000")
+	it.Equal(3*time.Second, SdWatchdogTimeout())
+
+	os.Setenv(EnvSdWatchdogPid, strconv.Itoa(os.Getpid()))
+	it.Equal(3*time.Second, SdWatchdogTimeout())
+
+	// it should be disabled for other process
+	os.Setenv(EnvSdWatchdogPid, strconv.Itoa(os.Getpid()+1))
+	it.Zero(SdWatchdogTimeout())
+
+	os.Unsetenv(EnvSdWatchdogPid)
+	os.Setenv(EnvSdWatchdogUsec, "invalid")
+	it.Zero(SdWatchdogTimeout())
+}
@@ -1,17 +1,47 @@
 package listeners
 
 import (
+	"context"
+	"crypto/tls"
 	"net"
+	"os"
 
 	"github.com/dolab/gogo/pkgs/hooks"
 )
 
 // A Interface extends net.Listener with custom actions
 type Interface interface {
+	WithTLSConfig(cfg *tls.Config) Interface
+	WithProxyProtocol(trusted []*net.IPNet) Interface
+	WithLimits(maxConns, maxConnsPerIP int) Interface
 	Listen(network, address string) (net.Listener, error)
 	Shutdown()
 	// Serve(server *http.Server)
 
+	// for handing over to child process
+	Network() string
+	Address() string
+	Inherited() bool
+	File() (*os.File, error)
+
+	// counters of connections
+	Stats() Stats
+
 	// hooks
+	ConnContext(ctx context.Context, c net.Conn) context.Context
 	RequestReceivedHook() hooks.NamedHook
 }
+
+type contextKey int
+
+const (
+	ctxListenerKey contextKey = iota + 1
+)
+
+// FromContext returns listener which accepted the connection of request context,
+// it returns nil if there is no listener bound.
+func FromContext(ctx context.Context) Interface {
+	l, _ := ctx.Value(ctxListenerKey).(Interface)
+
+	return l
+}
@@ -0,0 +1,103 @@
+package watcher
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// A Watcher polls modification time of files and invokes callback when any changed.
+type Watcher struct {
+	interval time.Duration
+	callback func()
+
+	mux      sync.Mutex
+	modtimes map[string]time.Time
+	stopc    chan struct{}
+}
+
+// New creates *Watcher of files with polling interval and callback
+func New(interval time.Duration, callback func(), files ...string) *Watcher {
+	w := &Watcher{
+		interval: interval,
+		callback: callback,
+		modtimes: make(map[string]time.Time, len(files)),
+	}
+
+	for _, file := range files {
+		w.modtimes[file] = modtime(file)
+	}
+
+	return w
+}
+
+// Start starts polling in background, it does nothing if already started.
+func (w *Watcher) Start() {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	if w.stopc != nil {
+		return
+	}
+
+	w.stopc = make(chan struct{})
+
+	go w.loop(w.stopc)
+}
+
+// Stop stops polling, it's safe to call Stop many times.
+func (w *Watcher) Stop() {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	if w.stopc == nil {
+		return
+	}
+
+	close(w.stopc)
+	w.stopc = nil
+}
+
+// Changed returns true if any file changed since last checking
+func (w *Watcher) Changed() (changed bool) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	for file, last := range w.modtimes {
+		current := modtime(file)
+		if !current.Equal(last) {
+			w.modtimes[file] = current
+
+			changed = true
+		}
+	}
+
+	return
+}
+
+func (w *Watcher) loop(stopc chan struct{}) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if w.Changed() {
+				w.callback()
+			}
+
+		case <-stopc:
+			return
+		}
+	}
+}
+
+// modtime returns zero time.Time if file does not exist
+func modtime(file string) time.Time {
+	finfo, err := os.Stat(file)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return finfo.ModTime()
+}
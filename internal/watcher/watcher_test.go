@@ -0,0 +1,49 @@
+package watcher
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/golib/assert"
+)
+
+func Test_Watcher(t *testing.T) {
+	it := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "gogo-watcher")
+	if !it.Nil(err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	filename := path.Join(dir, "watched.yml")
+	ioutil.WriteFile(filename, []byte("v1"), 0644)
+
+	changedc := make(chan struct{}, 1)
+
+	w := New(10*time.Millisecond, func() {
+		changedc <- struct{}{}
+	}, filename)
+	w.Start()
+	defer w.Stop()
+
+	// it should not be changed
+	it.False(w.Changed())
+
+	// it should invoke callback after modified
+	modtime := time.Now().Add(time.Second)
+	os.Chtimes(filename, modtime, modtime)
+
+	select {
+	case <-changedc:
+	case <-time.After(time.Second):
+		t.Error("Expected callback invoked, but timeout")
+	}
+
+	// it should be safe to stop many times
+	w.Stop()
+	w.Stop()
+}
@@ -0,0 +1,197 @@
+package gogo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dolab/gogo/pkgs/interceptors"
+)
+
+// A NopComponent implements Component with doing nothing, it's useful for
+// embedding into components which care about parts of lifecycle.
+type NopComponent struct{}
+
+// OnStart implements Component
+func (NopComponent) OnStart(ctx context.Context) error {
+	return nil
+}
+
+// OnReady implements Component
+func (NopComponent) OnReady(ctx context.Context) error {
+	return nil
+}
+
+// OnShutdown implements Component
+func (NopComponent) OnShutdown(ctx context.Context) error {
+	return nil
+}
+
+// interceptorComponent adapts interceptors.Shutdowner to Component
+type interceptorComponent struct {
+	NopComponent
+
+	name       string
+	shutdowner interceptors.Shutdowner
+}
+
+func (ic *interceptorComponent) Name() string {
+	return ic.name
+}
+
+func (ic *interceptorComponent) OnShutdown(ctx context.Context) error {
+	return ic.shutdowner.Shutdown()
+}
+
+// WithComponents registers lifecycle-aware components of server. Components are
+// started in order of registration before listening, notified in the same order
+// after listening and stopped in reverse order after connections drained.
+//
+// NOTE: It must be called before Start.
+func (s *AppServer) WithComponents(components ...Component) {
+	s.localMux.Lock()
+	s.localComponents = append(s.localComponents, components...)
+	s.localMux.Unlock()
+}
+
+// withShutdowner registers interceptor as a component if it implements
+// interceptors.Shutdowner. It's safe to register the same interceptor for
+// different phases.
+//
+// NOTE: The caller must hold localMux.
+func (s *AppServer) withShutdowner(m interceptors.Interface) {
+	shutdowner, ok := m.(interceptors.Shutdowner)
+	if !ok {
+		return
+	}
+
+	for _, c := range s.localComponents {
+		if ic, ok := c.(*interceptorComponent); ok && ic.shutdowner == shutdowner {
+			return
+		}
+	}
+
+	s.localComponents = append(s.localComponents, &interceptorComponent{
+		name:       m.Name(),
+		shutdowner: shutdowner,
+	})
+}
+
+// startComponents invokes OnStart of all components in order within component
+// timeout. Components started are stopped in reverse order if anyone failed.
+func (s *AppServer) startComponents(ctx context.Context) error {
+	s.localMux.RLock()
+	components := s.localComponents
+	s.localMux.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, s.componentTimeout())
+	defer cancel()
+
+	for i, c := range components {
+		err := invokeComponent(ctx, c.OnStart)
+		if err != nil {
+			s.localMux.Lock()
+			s.localStarted = i
+			s.localMux.Unlock()
+
+			s.stopComponents()
+
+			return fmt.Errorf("%s.OnStart(): %v", componentName(c), err)
+		}
+	}
+
+	s.localMux.Lock()
+	s.localStarted = len(components)
+	s.localMux.Unlock()
+
+	return nil
+}
+
+// readyComponents invokes OnReady of all components started in order within
+// component timeout.
+func (s *AppServer) readyComponents(ctx context.Context) error {
+	s.localMux.RLock()
+	components := s.localComponents[:s.localStarted]
+	s.localMux.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, s.componentTimeout())
+	defer cancel()
+
+	for _, c := range components {
+		err := invokeComponent(ctx, c.OnReady)
+		if err != nil {
+			return fmt.Errorf("%s.OnReady(): %v", componentName(c), err)
+		}
+	}
+
+	return nil
+}
+
+// stopComponents invokes OnShutdown of all components started in reverse order
+// within component timeout. It returns the first error and logs others.
+func (s *AppServer) stopComponents() (err error) {
+	s.localMux.Lock()
+	components := s.localComponents[:s.localStarted]
+	s.localStarted = 0
+	s.localMux.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.componentTimeout())
+	defer cancel()
+
+	log := s.loggerNew("GOGO")
+	for i := len(components) - 1; i >= 0; i-- {
+		c := components[i]
+
+		cerr := invokeComponent(ctx, c.OnShutdown)
+		if cerr != nil {
+			cerr = fmt.Errorf("%s.OnShutdown(): %v", componentName(c), cerr)
+
+			log.Error(cerr)
+
+			if err == nil {
+				err = cerr
+			}
+		}
+	}
+
+	return
+}
+
+func (s *AppServer) componentTimeout() time.Duration {
+	timeout := DefaultComponentTimeout
+	if config := s.config.Section(); config.Server.ComponentTimeout > 0 {
+		timeout = config.Server.ComponentTimeout
+	}
+
+	return time.Duration(timeout) * time.Second
+}
+
+// invokeComponent calls fn with ctx and returns ctx.Err() if fn does not return
+// before ctx done.
+func invokeComponent(ctx context.Context, fn func(context.Context) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- fn(ctx)
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// componentName returns Name() of component if defined, otherwise returns its type.
+func componentName(c Component) string {
+	if namer, ok := c.(interface{ Name() string }); ok {
+		return namer.Name()
+	}
+
+	return fmt.Sprintf("%T", c)
+}
@@ -0,0 +1,181 @@
+package gogo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golib/assert"
+)
+
+type stubComponent struct {
+	NopComponent
+
+	name   string
+	mux    *sync.Mutex
+	events *[]string
+	err    error
+	ready  error
+}
+
+func (stub *stubComponent) Name() string {
+	return stub.name
+}
+
+func (stub *stubComponent) OnStart(ctx context.Context) error {
+	stub.record("start")
+
+	return stub.err
+}
+
+func (stub *stubComponent) OnReady(ctx context.Context) error {
+	stub.record("ready")
+
+	return stub.ready
+}
+
+func (stub *stubComponent) OnShutdown(ctx context.Context) error {
+	stub.record("shutdown")
+
+	return nil
+}
+
+func (stub *stubComponent) record(event string) {
+	stub.mux.Lock()
+	*stub.events = append(*stub.events, stub.name+"."+event)
+	stub.mux.Unlock()
+}
+
+type stubShutdownInterceptor struct {
+	*stubInterceptor
+
+	shutdowns int
+}
+
+func (stub *stubShutdownInterceptor) Shutdown() error {
+	stub.shutdowns++
+
+	return nil
+}
+
+func Test_ServerWithComponents(t *testing.T) {
+	it := assert.New(t)
+
+	var (
+		mux    sync.Mutex
+		events []string
+	)
+
+	server := fakeTimeoutServer()
+	server.WithComponents(
+		&stubComponent{name: "db", mux: &mux, events: &events},
+		&stubComponent{name: "cache", mux: &mux, events: &events},
+	)
+
+	// it should pick up interceptors with shutdown
+	interceptor := &stubShutdownInterceptor{
+		stubInterceptor: &stubInterceptor{
+			name: "shutdown@testing",
+			apply: func(w http.ResponseWriter, r *http.Request) bool {
+				return true
+			},
+		},
+	}
+	it.Nil(server.WithRequestReceived(interceptor))
+	it.Nil(server.WithResponseAlways(interceptor))
+
+	err := server.Start(context.Background())
+	if !it.Nil(err) {
+		return
+	}
+	it.Equal([]string{"db.start", "cache.start", "db.ready", "cache.ready"}, events)
+
+	events = nil
+
+	it.Nil(server.Stop(context.Background()))
+	it.Equal([]string{"cache.shutdown", "db.shutdown"}, events)
+	it.Equal(1, interceptor.shutdowns)
+}
+
+func Test_ServerWithComponentsStartError(t *testing.T) {
+	it := assert.New(t)
+
+	var (
+		mux    sync.Mutex
+		events []string
+	)
+
+	server := fakeTimeoutServer()
+	server.WithComponents(
+		&stubComponent{name: "db", mux: &mux, events: &events},
+		&stubComponent{name: "cache", mux: &mux, events: &events, err: errors.New("unavailable")},
+		&stubComponent{name: "queue", mux: &mux, events: &events},
+	)
+
+	// it should stop components started in reverse order
+	err := server.Start(context.Background())
+	if it.NotNil(err) {
+		it.Equal("cache.OnStart(): unavailable", err.Error())
+		it.Equal([]string{"db.start", "cache.start", "db.shutdown"}, events)
+		it.Empty(server.Address())
+	}
+}
+
+func Test_ServerWithComponentsReadyError(t *testing.T) {
+	it := assert.New(t)
+
+	var (
+		mux    sync.Mutex
+		events []string
+	)
+
+	component := &stubComponent{name: "db", mux: &mux, events: &events, ready: errors.New("not ready")}
+
+	server := fakeTimeoutServer()
+	server.WithComponents(component)
+
+	listenerHooks := func() (n int) {
+		for _, hook := range server.RequestReceived.Hooks() {
+			if strings.HasPrefix(hook.Name, "__listener@") {
+				n++
+			}
+		}
+
+		return
+	}
+
+	// it should remove hooks of listeners closed for retrying
+	for i := 0; i < 2; i++ {
+		err := server.Start(context.Background())
+		if it.NotNil(err) {
+			it.Equal("db.OnReady(): not ready", err.Error())
+			it.Equal(0, listenerHooks())
+		}
+	}
+
+	component.ready = nil
+
+	err := server.Start(context.Background())
+	if it.Nil(err) {
+		it.Equal(1, listenerHooks())
+		it.Nil(server.Stop(context.Background()))
+	}
+}
+
+func Test_invokeComponentWithTimeout(t *testing.T) {
+	it := assert.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := invokeComponent(ctx, func(ctx context.Context) error {
+		time.Sleep(time.Second)
+
+		return nil
+	})
+	it.Equal(context.DeadlineExceeded, err)
+}
@@ -0,0 +1,175 @@
+package gogo
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dolab/gogo/pkgs/hooks"
+)
+
+// A LimitStatus defines status of a limit adjustable at runtime
+type LimitStatus struct {
+	Limit     int        `json:"limit"`
+	Default   int        `json:"default"`              // configured value, it's restored after expired
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // nil means never expired
+}
+
+// A LimitsStatus defines status of throttle and demotion of server, it's nil
+// for limit disabled.
+type LimitsStatus struct {
+	Throttle *LimitStatus `json:"throttle,omitempty"`
+	Demotion *LimitStatus `json:"demotion,omitempty"`
+}
+
+// A LimitsUpdate defines changes of limits, nil value means no change.
+type LimitsUpdate struct {
+	Throttle *int `json:"throttle,omitempty"`
+	Demotion *int `json:"demotion,omitempty"`
+	TTL      int  `json:"ttl,omitempty"` // unit in second, the configured value is restored after ttl if positive
+}
+
+// runtimeLimit wraps hooks.Limiter with restoring of configured value after ttl.
+type runtimeLimit struct {
+	name     string
+	limiter  hooks.Limiter
+	defaults int
+
+	mux       sync.Mutex
+	timer     *time.Timer
+	expiresAt time.Time
+}
+
+func newRuntimeLimit(name string, limiter hooks.Limiter) *runtimeLimit {
+	return &runtimeLimit{
+		name:     name,
+		limiter:  limiter,
+		defaults: limiter.Limit(),
+	}
+}
+
+// set changes limit, and restores the configured value after ttl if positive.
+// All changes are logged with actor for auditing.
+func (l *runtimeLimit) set(max int, ttl time.Duration, actor string, log Logger) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	// cancel restoring of previous changes
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+		l.expiresAt = time.Time{}
+	}
+
+	prev := l.limiter.Limit()
+	l.limiter.SetLimit(max)
+
+	log.Warnf("Adjusted %s from %d to %d by %s, ttl=%s", l.name, prev, l.limiter.Limit(), actor, ttl)
+
+	if ttl <= 0 {
+		return
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(ttl, func() {
+		l.mux.Lock()
+		defer l.mux.Unlock()
+
+		// changed again before expired
+		if l.timer != timer {
+			return
+		}
+
+		l.limiter.SetLimit(l.defaults)
+		l.timer = nil
+		l.expiresAt = time.Time{}
+
+		log.Warnf("Restored %s to %d after ttl=%s", l.name, l.defaults, ttl)
+	})
+
+	l.timer = timer
+	l.expiresAt = time.Now().Add(ttl)
+}
+
+func (l *runtimeLimit) status() *LimitStatus {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	status := &LimitStatus{
+		Limit:   l.limiter.Limit(),
+		Default: l.defaults,
+	}
+	if !l.expiresAt.IsZero() {
+		expiresAt := l.expiresAt
+		status.ExpiresAt = &expiresAt
+	}
+
+	return status
+}
+
+// Limits returns status of throttle and demotion of server
+func (s *AppServer) Limits() *LimitsStatus {
+	s.localMux.RLock()
+	throttle, demotion := s.localThrottle, s.localDemotion
+	s.localMux.RUnlock()
+
+	status := &LimitsStatus{}
+	if throttle != nil {
+		status.Throttle = throttle.status()
+	}
+	if demotion != nil {
+		status.Demotion = demotion.status()
+	}
+
+	return status
+}
+
+// SetThrottle changes max throughput / per second at runtime, the configured
+// value is restored after ttl if positive. It disables throttling if max is zero.
+//
+// NOTE: It returns ErrLimitDisabled if neither throttle nor admin is configured.
+func (s *AppServer) SetThrottle(max int, ttl time.Duration) error {
+	return s.adjustLimits(&LimitsUpdate{Throttle: &max}, ttl, "AppServer.SetThrottle")
+}
+
+// SetDemotion changes max concurrency at runtime, the configured value is
+// restored after ttl if positive. It disables demotion if max is zero.
+//
+// NOTE: It returns ErrLimitDisabled if neither demotion nor admin is configured.
+func (s *AppServer) SetDemotion(max int, ttl time.Duration) error {
+	return s.adjustLimits(&LimitsUpdate{Demotion: &max}, ttl, "AppServer.SetDemotion")
+}
+
+func (s *AppServer) adjustLimits(update *LimitsUpdate, ttl time.Duration, actor string) error {
+	s.localMux.RLock()
+	throttle, demotion := s.localThrottle, s.localDemotion
+	s.localMux.RUnlock()
+
+	// validate all before changing anyone
+	if update.Throttle != nil {
+		if throttle == nil {
+			return ErrLimitDisabled
+		}
+		if *update.Throttle < 0 {
+			return ErrLimitInvalid
+		}
+	}
+	if update.Demotion != nil {
+		if demotion == nil {
+			return ErrLimitDisabled
+		}
+		if *update.Demotion < 0 {
+			return ErrLimitInvalid
+		}
+	}
+
+	log := s.loggerNew("GOGO")
+
+	if update.Throttle != nil {
+		throttle.set(*update.Throttle, ttl, actor, log)
+	}
+	if update.Demotion != nil {
+		demotion.set(*update.Demotion, ttl, actor, log)
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package gogo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golib/assert"
+)
+
+func Test_ServerSetThrottle(t *testing.T) {
+	it := assert.New(t)
+	logger := NewAppLogger("nil", "")
+	config, _ := fakeConfig("application.admin.yml")
+
+	server := NewAppServer(config, logger)
+	server.prepare()
+
+	status := server.Limits()
+	if it.NotNil(status.Throttle) && it.NotNil(status.Demotion) {
+		it.Equal(100, status.Throttle.Limit)
+		it.Equal(100, status.Throttle.Default)
+		it.Nil(status.Throttle.ExpiresAt)
+
+		// it should be disabled without config
+		it.Equal(0, status.Demotion.Limit)
+	}
+
+	// it should restore after ttl
+	it.Nil(server.SetThrottle(10, 20*time.Millisecond))
+	it.Nil(server.SetDemotion(5, 0))
+
+	status = server.Limits()
+	it.Equal(10, status.Throttle.Limit)
+	it.NotNil(status.Throttle.ExpiresAt)
+	it.Equal(5, status.Demotion.Limit)
+	it.Nil(status.Demotion.ExpiresAt)
+
+	for i := 0; i < 100 && server.Limits().Throttle.Limit != 100; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	status = server.Limits()
+	it.Equal(100, status.Throttle.Limit)
+	it.Nil(status.Throttle.ExpiresAt)
+	it.Equal(5, status.Demotion.Limit)
+
+	// it should not restore if changed again
+	it.Nil(server.SetThrottle(10, 20*time.Millisecond))
+	it.Nil(server.SetThrottle(20, 0))
+
+	time.Sleep(50 * time.Millisecond)
+	it.Equal(20, server.Limits().Throttle.Limit)
+
+	it.Equal(ErrLimitInvalid, server.SetThrottle(-1, 0))
+
+	// it should return error if disabled
+	server = fakeServer()
+	server.prepare()
+
+	it.Nil(server.Limits().Throttle)
+	it.Equal(ErrLimitDisabled, server.SetThrottle(10, 0))
+	it.Equal(ErrLimitDisabled, server.SetDemotion(10, 0))
+}
+
+func Test_LimitsHandle(t *testing.T) {
+	it := assert.New(t)
+	logger := NewAppLogger("nil", "")
+	config, _ := fakeConfig("application.admin.yml")
+
+	server := NewAppServer(config, logger)
+	server.prepare()
+
+	newRequest := func(method, body string) *http.Request {
+		r := httptest.NewRequest(method, GogoAdmin+"limits", strings.NewReader(body))
+		r.RemoteAddr = "10.0.0.1:8080"
+		r.Header.Set(DefaultAdminTokenHeader, "s3cret")
+
+		return r
+	}
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, newRequest(http.MethodGet, ""))
+	if it.Equal(http.StatusOK, w.Code) {
+		var status LimitsStatus
+		if it.Nil(json.NewDecoder(w.Body).Decode(&status)) {
+			it.Equal(100, status.Throttle.Limit)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, newRequest(http.MethodPut, `{"throttle": 50, "demotion": 10, "ttl": 60}`))
+	if it.Equal(http.StatusOK, w.Code) {
+		var status LimitsStatus
+		if it.Nil(json.NewDecoder(w.Body).Decode(&status)) {
+			it.Equal(50, status.Throttle.Limit)
+			it.Equal(100, status.Throttle.Default)
+			it.NotNil(status.Throttle.ExpiresAt)
+			it.Equal(10, status.Demotion.Limit)
+		}
+	}
+
+	// it should reject invalid values without changes
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, newRequest(http.MethodPut, `{"throttle": 10, "demotion": -1}`))
+	it.Equal(http.StatusBadRequest, w.Code)
+	it.Equal(50, server.Limits().Throttle.Limit)
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, newRequest(http.MethodPut, `{"throttle":`))
+	it.Equal(http.StatusBadRequest, w.Code)
+
+	// it should deny without token
+	r := newRequest(http.MethodPut, `{"throttle": 10}`)
+	r.Header.Del(DefaultAdminTokenHeader)
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusForbidden, w.Code)
+	it.Equal(50, server.Limits().Throttle.Limit)
+}
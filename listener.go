@@ -0,0 +1,271 @@
+package gogo
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/dolab/gogo/internal/listeners"
+	"github.com/dolab/gogo/internal/watcher"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// ListenerStats defines counters of connections for a listener of AppServer
+type ListenerStats struct {
+	Network  string `json:"network"`
+	Address  string `json:"address"`
+	Accepted int64  `json:"accepted"` // total of connections accepted
+	Rejected int64  `json:"rejected"` // total of connections rejected by max_conns_per_ip
+	Active   int64  `json:"active"`   // connections not closed yet
+}
+
+// appListener binds a listener with its own *http.Server
+type appListener struct {
+	config   *ListenerConfig
+	network  string
+	address  string
+	listener listeners.Interface
+	conn     net.Listener
+	server   *http.Server
+	certs    *listeners.Certificates
+	watcher  *watcher.Watcher
+	h2c      *h2cTracker // nil if h2c disabled
+}
+
+// newAppListener announces on the address of config and returns *appListener
+// with *http.Server served by handler.
+func newAppListener(config *ListenerConfig, handler http.Handler) (*appListener, error) {
+	var (
+		rtimeout       = DefaultRequestTimeout
+		wtimeout       = DefaultResponseTimeout
+		maxHeaderBytes = 0
+	)
+
+	if config.RTimeout > 0 {
+		rtimeout = config.RTimeout
+	}
+	if config.WTimeout > 0 {
+		wtimeout = config.WTimeout
+	}
+	if config.MaxHeaderBytes > 0 {
+		maxHeaderBytes = config.MaxHeaderBytes
+	}
+
+	network, addr := config.Resolve()
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: time.Duration(rtimeout) * time.Second,
+		ReadTimeout:       time.Duration(rtimeout) * time.Second,
+		WriteTimeout:      time.Duration(wtimeout) * time.Second,
+		MaxHeaderBytes:    maxHeaderBytes,
+	}
+
+	// NOTE: http.Server uses ReadTimeout for idle connections if not set.
+	if config.ITimeout > 0 {
+		server.IdleTimeout = time.Duration(config.ITimeout) * time.Second
+	}
+
+	listener := listeners.New(config.HTTP2).WithLimits(config.MaxConns, config.MaxConnsPerIP)
+
+	var (
+		certs   *listeners.Certificates
+		tracker *h2cTracker
+	)
+	if config.Ssl {
+		var err error
+
+		certs, err = listeners.NewCertificates(config.KeyPairs()...)
+		if err != nil {
+			return nil, &TLSError{network, addr, err}
+		}
+
+		server.TLSConfig = certs.TLSConfig()
+
+		// for mutual tls
+		server.TLSConfig.ClientAuth, err = listeners.ParseClientAuth(config.SslClientAuth)
+		if err != nil {
+			return nil, &TLSError{network, addr, err}
+		}
+		if config.SslClientCA != "" {
+			server.TLSConfig.ClientCAs, err = listeners.NewClientCAs(config.SslClientCA)
+			if err != nil {
+				return nil, &TLSError{network, addr, fmt.Errorf("client ca %s: %v", config.SslClientCA, err)}
+			}
+		} else if auth := server.TLSConfig.ClientAuth; auth == tls.VerifyClientCertIfGiven || auth == tls.RequireAndVerifyClientCert {
+			// NOTE: client certificates would be verified by system roots without ca
+			return nil, &TLSError{network, addr, fmt.Errorf("client ca is required for client auth %s", config.SslClientAuth)}
+		}
+
+		if config.HTTP2 {
+			err := http2.ConfigureServer(server, nil)
+			if err != nil {
+				return nil, &TLSError{network, addr, err}
+			}
+		} else {
+			server.TLSConfig.NextProtos = []string{"http/1.1"}
+		}
+
+		listener.WithTLSConfig(server.TLSConfig)
+	} else if config.H2C {
+		// NOTE: http2.Server must be configured with http.Server for draining
+		// connections by Shutdown.
+		h2s := &http2.Server{}
+
+		err := http2.ConfigureServer(server, h2s)
+		if err != nil {
+			return nil, &ServeError{"ServeH2C", network, addr, err}
+		}
+
+		// tls config is useless for plaintext
+		server.TLSConfig = nil
+
+		tracker = &h2cTracker{
+			handler: h2c.NewHandler(handler, h2s),
+		}
+
+		server.Handler = tracker
+	}
+
+	if config.ProxyProtocol {
+		// NOTE: headers of untrusted sources are spoofable
+		if len(config.ProxyTrusted) == 0 {
+			return nil, ErrProxyUntrusted
+		}
+
+		trusted, err := listeners.ParseCIDRs(config.ProxyTrusted)
+		if err != nil {
+			return nil, fmt.Errorf("listeners.ParseCIDRs(%v): %v", config.ProxyTrusted, err)
+		}
+
+		listener.WithProxyProtocol(trusted)
+	}
+
+	conn, err := listener.Listen(network, addr)
+	if err != nil {
+		return nil, &ListenError{network, addr, err}
+	}
+
+	server.ConnContext = listener.ConnContext
+	server.RegisterOnShutdown(listener.Shutdown)
+
+	return &appListener{
+		config:   config,
+		network:  network,
+		address:  addr,
+		listener: listener,
+		conn:     conn,
+		server:   server,
+		certs:    certs,
+		h2c:      tracker,
+	}, nil
+}
+
+// Addr returns bound address of the listener
+func (al *appListener) Addr() string {
+	return al.conn.Addr().String()
+}
+
+// watch starts checking changes of certificate files within interval, and
+// reloads certificates if changed.
+func (al *appListener) watch(interval time.Duration, log Logger) {
+	if al.certs == nil {
+		return
+	}
+
+	al.watcher = watcher.New(interval, func() {
+		al.reload(log)
+	}, al.certs.Files()...)
+	al.watcher.Start()
+
+	al.server.RegisterOnShutdown(al.watcher.Stop)
+}
+
+// reload reloads certificates of the listener, it keeps the old if failed.
+func (al *appListener) reload(log Logger) {
+	if al.certs == nil {
+		return
+	}
+
+	err := al.certs.Reload()
+	if err != nil {
+		log.Errorf("Reload certificates of %s://%s: %v, keep the old", al.network, al.address, err)
+		return
+	}
+
+	log.Infof("Reloaded certificates of %s://%s", al.network, al.address)
+}
+
+// shutdown shuts down the listener gracefully, it waits for h2c connections
+// which are hijacked from http.Server and not tracked by its Shutdown.
+func (al *appListener) shutdown(ctx context.Context) error {
+	err := al.server.Shutdown(ctx)
+	if err != nil || al.h2c == nil {
+		return err
+	}
+
+	return al.h2c.wait(ctx)
+}
+
+// serve accepts incoming connections, it returns nil if server closed, otherwise
+// returns *ServeError.
+//
+// NOTE: tls handshake is done by the listener.
+func (al *appListener) serve() error {
+	err := al.server.Serve(al.conn)
+	if err == nil || err == http.ErrServerClosed {
+		return nil
+	}
+
+	op := "Serve"
+	switch {
+	case al.config.Ssl && al.config.HTTP2:
+		op = "ServeHTTP2"
+
+	case !al.config.Ssl && al.config.H2C:
+		op = "ServeH2C"
+
+	case al.config.Ssl:
+		op = "ServeTLS"
+	}
+
+	return &ServeError{op, al.network, al.address, err}
+}
+
+// h2cTracker counts active connections of h2c, each of them is served by a
+// call of ServeHTTP until closed.
+type h2cTracker struct {
+	handler http.Handler
+	active  int64
+}
+
+// ServeHTTP implements http.Handler
+func (t *h2cTracker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt64(&t.active, 1)
+	defer atomic.AddInt64(&t.active, -1)
+
+	t.handler.ServeHTTP(w, r)
+}
+
+// wait blocks until all connections closed or ctx done
+func (t *h2cTracker) wait(ctx context.Context) error {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for atomic.LoadInt64(&t.active) > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-ticker.C:
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,354 @@
+package gogo
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dolab/httpdispatch"
+)
+
+// methodOrders defines order of methods in Allow header, others are sorted by
+// name after them, and OPTIONS is always the last.
+var methodOrders = map[string]int{
+	http.MethodGet:    1,
+	http.MethodHead:   2,
+	http.MethodPost:   3,
+	http.MethodPut:    4,
+	http.MethodPatch:  5,
+	http.MethodDelete: 6,
+}
+
+// sortMethods sorts methods in order of Allow header
+func sortMethods(methods []string) {
+	sort.SliceStable(methods, func(i, j int) bool {
+		mi, mj := methods[i], methods[j]
+		if mi == http.MethodOptions || mj == http.MethodOptions {
+			return mj == http.MethodOptions && mi != http.MethodOptions
+		}
+
+		oi, iok := methodOrders[mi]
+		oj, jok := methodOrders[mj]
+		switch {
+		case iok && jok:
+			return oi < oj
+
+		case iok != jok:
+			return iok
+		}
+
+		return mi < mj
+	})
+}
+
+// routeTree defines states shared by all groups of the same routes tree, e.g.
+// automatic OPTIONS.
+type routeTree struct {
+	mux         sync.RWMutex
+	autoOptions bool
+	cors        *corsPolicy
+}
+
+func newRouteTree() *routeTree {
+	return &routeTree{}
+}
+
+// isAutoOptions returns true if OPTIONS and 405 are answered automatically
+func (t *routeTree) isAutoOptions() bool {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	return t.autoOptions
+}
+
+// autoOptionsPolicy returns whether automatic OPTIONS is on, and its cors policy
+func (t *routeTree) autoOptionsPolicy() (bool, *corsPolicy) {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+
+	return t.autoOptions, t.cors
+}
+
+// enableAutoOptions turns on automatic OPTIONS with cors policy
+func (t *routeTree) enableAutoOptions(cors *corsPolicy) {
+	t.mux.Lock()
+	t.autoOptions = true
+	t.cors = cors
+	t.mux.Unlock()
+}
+
+// serve answers OPTIONS requests with Allow header and CORS headers of preflight,
+// and sets CORS headers for actual requests. It returns true if the request has
+// been answered.
+//
+// NOTE: OPTIONS routes registered take precedence over automatic replies.
+func (t *routeTree) serve(w http.ResponseWriter, r *http.Request, handler Handler) bool {
+	t.mux.RLock()
+	auto, cors := t.autoOptions, t.cors
+	t.mux.RUnlock()
+
+	if !auto {
+		return false
+	}
+
+	dispatcher, ok := handler.(*httpdispatch.Dispatcher)
+	if !ok {
+		return false
+	}
+
+	origin := r.Header.Get("Origin")
+
+	if r.Method != http.MethodOptions {
+		if cors != nil && origin != "" {
+			cors.applyActual(w.Header(), origin)
+		}
+
+		// filter Allow header of 405 for requests routed nowhere
+		if found, _, _ := dispatcher.Lookup(r.Method, r.URL.Path); found != nil {
+			return false
+		}
+
+		aw := &allowWriter{
+			ResponseWriter: w,
+			dispatcher:     dispatcher,
+			path:           r.URL.Path,
+		}
+
+		dispatcher.ServeHTTP(aw, r)
+
+		if aw.dropped {
+			dispatcher.NotFound.ServeHTTP(w, r)
+		}
+		return true
+	}
+
+	if custom, _, _ := dispatcher.Lookup(http.MethodOptions, r.URL.Path); custom != nil {
+		return false
+	}
+
+	// Allow header is set by dispatcher without responding, others are responded by NotFound
+	aw := &allowWriter{
+		ResponseWriter: w,
+		dispatcher:     dispatcher,
+		path:           r.URL.Path,
+	}
+
+	dispatcher.ServeHTTP(aw, r)
+
+	if aw.wrote {
+		return true
+	}
+
+	header := w.Header()
+
+	allow := allowedMethods(dispatcher, r.URL.Path, header.Get("Allow"))
+	if len(allow) == 0 {
+		header.Del("Allow")
+
+		dispatcher.NotFound.ServeHTTP(w, r)
+		return true
+	}
+
+	header.Set("Allow", strings.Join(allow, ", "))
+
+	if cors != nil && origin != "" && r.Header.Get("Access-Control-Request-Method") != "" {
+		cors.applyPreflight(header, r, allow)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
+
+// allowedMethods returns methods of Allow header computed by dispatcher in order,
+// methods of routes with params mismatched constraints are excluded. OPTIONS is
+// included if anyone left.
+func allowedMethods(dispatcher *httpdispatch.Dispatcher, path, allow string) []string {
+	var methods []string
+	for _, method := range strings.Split(allow, ",") {
+		method = strings.TrimSpace(method)
+		if method == "" || method == http.MethodOptions {
+			continue
+		}
+
+		handler, ps, _ := dispatcher.Lookup(method, path)
+		if handler == nil {
+			continue
+		}
+
+		if ch, ok := handler.(*ContextHandle); ok && !ch.constraints.match(ps) {
+			continue
+		}
+
+		methods = append(methods, method)
+	}
+
+	if len(methods) == 0 {
+		return nil
+	}
+
+	methods = append(methods, http.MethodOptions)
+	sortMethods(methods)
+
+	return methods
+}
+
+// allowWriter filters Allow header of 405 responded by dispatcher with constraints
+// of routes, the response is dropped if there is no method allowed any more.
+type allowWriter struct {
+	http.ResponseWriter
+
+	dispatcher *httpdispatch.Dispatcher
+	path       string
+	wrote      bool
+	dropped    bool
+}
+
+// WriteHeader implements http.ResponseWriter interface
+func (aw *allowWriter) WriteHeader(code int) {
+	if aw.wrote {
+		return
+	}
+	aw.wrote = true
+
+	if code == http.StatusMethodNotAllowed {
+		header := aw.Header()
+
+		allow := allowedMethods(aw.dispatcher, aw.path, header.Get("Allow"))
+		if len(allow) == 0 {
+			header.Del("Allow")
+
+			aw.dropped = true
+			return
+		}
+
+		header.Set("Allow", strings.Join(allow, ", "))
+	}
+
+	aw.ResponseWriter.WriteHeader(code)
+}
+
+// Write implements http.ResponseWriter interface, it discards data if dropped.
+func (aw *allowWriter) Write(data []byte) (int, error) {
+	aw.WriteHeader(http.StatusOK)
+
+	if aw.dropped {
+		return len(data), nil
+	}
+
+	return aw.ResponseWriter.Write(data)
+}
+
+// corsPolicy defines CORS policy resolved of *CORSConfig
+type corsPolicy struct {
+	anyOrigin   bool
+	origins     map[string]bool
+	methods     []string
+	headers     string
+	exposed     string
+	credentials bool
+	maxAge      string
+}
+
+// newCORSPolicy returns policy of config, it returns ErrCORSCredentials if
+// credentials are allowed for any origin.
+func newCORSPolicy(config *CORSConfig) (*corsPolicy, error) {
+	policy := &corsPolicy{
+		origins:     make(map[string]bool),
+		headers:     strings.Join(config.AllowedHeaders, ", "),
+		exposed:     strings.Join(config.ExposedHeaders, ", "),
+		credentials: config.AllowCredentials,
+	}
+
+	for _, origin := range config.AllowedOrigins {
+		if origin == "*" {
+			policy.anyOrigin = true
+			continue
+		}
+
+		policy.origins[strings.ToLower(origin)] = true
+	}
+
+	if policy.anyOrigin && policy.credentials {
+		return nil, ErrCORSCredentials
+	}
+
+	for _, method := range config.AllowedMethods {
+		policy.methods = append(policy.methods, strings.ToUpper(method))
+	}
+	sortMethods(policy.methods)
+
+	if config.MaxAge > 0 {
+		policy.maxAge = strconv.Itoa(config.MaxAge)
+	}
+
+	return policy, nil
+}
+
+// allowOrigin returns value of Access-Control-Allow-Origin for origin, it returns
+// empty if origin is not allowed.
+func (p *corsPolicy) allowOrigin(origin string) string {
+	if p.anyOrigin {
+		return "*"
+	}
+
+	if p.origins[strings.ToLower(origin)] {
+		return origin
+	}
+
+	return ""
+}
+
+// apply sets headers of origin allowed, it returns false if origin is not allowed.
+func (p *corsPolicy) apply(header http.Header, origin string) bool {
+	header.Add("Vary", "Origin")
+
+	allowed := p.allowOrigin(origin)
+	if allowed == "" {
+		return false
+	}
+
+	header.Set("Access-Control-Allow-Origin", allowed)
+	if p.credentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+
+	return true
+}
+
+// applyActual sets CORS headers of actual requests
+func (p *corsPolicy) applyActual(header http.Header, origin string) {
+	if p.apply(header, origin) && p.exposed != "" {
+		header.Set("Access-Control-Expose-Headers", p.exposed)
+	}
+}
+
+// applyPreflight sets CORS headers of preflight requests, methods default to
+// allow if not configured.
+func (p *corsPolicy) applyPreflight(header http.Header, r *http.Request, allow []string) {
+	header.Add("Vary", "Access-Control-Request-Method")
+	header.Add("Vary", "Access-Control-Request-Headers")
+
+	if !p.apply(header, r.Header.Get("Origin")) {
+		return
+	}
+
+	methods := p.methods
+	if len(methods) == 0 {
+		methods = allow
+	}
+	header.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+
+	headers := p.headers
+	if headers == "" {
+		headers = r.Header.Get("Access-Control-Request-Headers")
+	}
+	if headers != "" {
+		header.Set("Access-Control-Allow-Headers", headers)
+	}
+
+	if p.maxAge != "" {
+		header.Set("Access-Control-Max-Age", p.maxAge)
+	}
+}
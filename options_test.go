@@ -0,0 +1,186 @@
+package gogo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golib/assert"
+)
+
+func Test_SortMethods(t *testing.T) {
+	it := assert.New(t)
+
+	methods := []string{"OPTIONS", "PROPFIND", "DELETE", "GET", "LOCK", "POST", "HEAD"}
+	sortMethods(methods)
+	it.Equal([]string{"GET", "HEAD", "POST", "DELETE", "LOCK", "PROPFIND", "OPTIONS"}, methods)
+}
+
+func Test_GroupWithAutoOptions(t *testing.T) {
+	it := assert.New(t)
+	server := fakeServer()
+
+	server.GET("/orders", fakePackageAction)
+	server.POST("/orders", fakePackageAction)
+	server.DELETE("/orders/:order", fakePackageAction)
+	server.Handle("PROPFIND", "/orders/:order", fakePackageAction)
+	server.GET("/users/:user<int>", fakePackageAction)
+	server.OPTIONS("/custom", func(ctx *Context) {
+		ctx.SetHeader("Allow", "custom")
+		ctx.SetStatus(http.StatusOK)
+	})
+
+	// it should be disabled by default
+	r, _ := http.NewRequest(http.MethodPatch, "/orders", nil)
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusNotFound, w.Code)
+	it.Empty(w.Header().Get("Allow"))
+
+	server.SetAutoOptions(nil)
+
+	testCases := []struct {
+		method string
+		uri    string
+		status int
+		allow  string
+	}{
+		{http.MethodOptions, "/orders", http.StatusNoContent, "GET, POST, OPTIONS"},
+		{http.MethodOptions, "/orders/1", http.StatusNoContent, "DELETE, PROPFIND, OPTIONS"},
+		{http.MethodOptions, "/custom", http.StatusOK, "custom"},
+		{http.MethodOptions, "/missing", http.StatusNotFound, ""},
+		{http.MethodPatch, "/orders", http.StatusMethodNotAllowed, "GET, POST, OPTIONS"},
+		{http.MethodGet, "/orders/1", http.StatusMethodNotAllowed, "DELETE, PROPFIND, OPTIONS"},
+		{http.MethodGet, "/missing", http.StatusNotFound, ""},
+		{http.MethodOptions, "/users/1", http.StatusNoContent, "GET, OPTIONS"},
+		{http.MethodPost, "/users/1", http.StatusMethodNotAllowed, "GET, OPTIONS"},
+		{http.MethodOptions, "/users/abc", http.StatusNotFound, ""},
+		{http.MethodPost, "/users/abc", http.StatusNotFound, ""},
+	}
+	for _, testCase := range testCases {
+		r, _ := http.NewRequest(testCase.method, testCase.uri, nil)
+		w := httptest.NewRecorder()
+
+		server.ServeHTTP(w, r)
+		it.Equal(testCase.status, w.Code, testCase.method, testCase.uri)
+		it.Equal(testCase.allow, w.Header().Get("Allow"), testCase.method, testCase.uri)
+	}
+}
+
+func Test_ServerWithCORS(t *testing.T) {
+	it := assert.New(t)
+	logger := NewAppLogger("nil", "")
+	config, _ := fakeConfig("application.cors.yml")
+
+	server := NewAppServer(config, logger)
+	server.GET("/orders", func(ctx *Context) {
+		ctx.Text("orders")
+	})
+	server.PUT("/orders/:order", fakePackageAction)
+
+	api := server.Host("api.example.com")
+	api.GET("/users", fakePackageAction)
+
+	server.prepare()
+
+	// preflight
+	r, _ := http.NewRequest(http.MethodOptions, "/orders/1", nil)
+	r.Header.Set("Origin", "https://app.example.com")
+	r.Header.Set("Access-Control-Request-Method", http.MethodPut)
+	r.Header.Set("Access-Control-Request-Headers", "Content-Type")
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusNoContent, w.Code)
+	it.Equal("PUT, OPTIONS", w.Header().Get("Allow"))
+	it.Equal("https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	it.Equal("true", w.Header().Get("Access-Control-Allow-Credentials"))
+	it.Equal("PUT, OPTIONS", w.Header().Get("Access-Control-Allow-Methods"))
+	it.Equal("Content-Type, X-Request-Id", w.Header().Get("Access-Control-Allow-Headers"))
+	it.Equal("600", w.Header().Get("Access-Control-Max-Age"))
+	it.Contains(w.Header()["Vary"], "Origin")
+
+	// preflight of origin not allowed
+	r, _ = http.NewRequest(http.MethodOptions, "/orders/1", nil)
+	r.Header.Set("Origin", "https://evil.example.com")
+	r.Header.Set("Access-Control-Request-Method", http.MethodPut)
+	w = httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusNoContent, w.Code)
+	it.Equal("PUT, OPTIONS", w.Header().Get("Allow"))
+	it.Empty(w.Header().Get("Access-Control-Allow-Origin"))
+	it.Empty(w.Header().Get("Access-Control-Allow-Methods"))
+
+	// actual request
+	r, _ = http.NewRequest(http.MethodGet, "/orders", nil)
+	r.Header.Set("Origin", "https://app.example.com")
+	w = httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusOK, w.Code)
+	it.Equal("orders", w.Body.String())
+	it.Equal("https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	it.Equal("X-Request-Id", w.Header().Get("Access-Control-Expose-Headers"))
+
+	// it should work for hosts
+	r, _ = http.NewRequest(http.MethodOptions, "/users", nil)
+	r.Host = "api.example.com"
+	w = httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusNoContent, w.Code)
+	it.Equal("GET, OPTIONS", w.Header().Get("Allow"))
+
+	// it should work for hosts registered after prepare
+	admin := server.Host("admin.example.com")
+	admin.GET("/users", fakePackageAction)
+
+	r, _ = http.NewRequest(http.MethodOptions, "/users", nil)
+	r.Host = "admin.example.com"
+	r.Header.Set("Origin", "https://app.example.com")
+	r.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	w = httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusNoContent, w.Code)
+	it.Equal("GET, OPTIONS", w.Header().Get("Allow"))
+	it.Equal("https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func Test_CORSPolicyWithAnyOrigin(t *testing.T) {
+	it := assert.New(t)
+
+	policy, err := newCORSPolicy(&CORSConfig{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{"post", "get"},
+	})
+	if it.Nil(err) {
+		it.Equal("*", policy.allowOrigin("https://app.example.com"))
+		it.Equal([]string{"GET", "POST"}, policy.methods)
+	}
+
+	// it should reject any origin with credentials
+	config := &CORSConfig{
+		AllowedOrigins:   []string{"https://app.example.com", "*"},
+		AllowCredentials: true,
+	}
+
+	_, err = newCORSPolicy(config)
+	it.Equal(ErrCORSCredentials, err)
+
+	server := fakeServer()
+	it.Panics(func() {
+		server.SetAutoOptions(config)
+	})
+
+	// it should match origin in case insensitive
+	policy, err = newCORSPolicy(&CORSConfig{
+		AllowedOrigins: []string{"https://App.example.com"},
+	})
+	if it.Nil(err) {
+		it.Equal("https://app.example.com", policy.allowOrigin("https://app.example.com"))
+		it.Empty(policy.allowOrigin("https://www.example.com"))
+	}
+}
@@ -0,0 +1,286 @@
+package hooks
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// A Limiter represents a limit of server which can be adjusted at runtime
+type Limiter interface {
+	Limit() int
+	SetLimit(max int)
+}
+
+// ServerThrottle limits throughput of requests per second.
+//
+// NOTE: burst value is 20% of throttle
+type ServerThrottle struct {
+	mux     sync.RWMutex
+	max     int
+	timeout time.Duration
+	limiter *rate.Limiter
+}
+
+// NewServerThrottle creates *ServerThrottle with max throughput / per second.
+// It passes all requests if max is less than 1.
+func NewServerThrottle(max int) *ServerThrottle {
+	throttle := &ServerThrottle{}
+	throttle.SetLimit(max)
+
+	return throttle
+}
+
+// Limit returns max throughput / per second
+func (throttle *ServerThrottle) Limit() int {
+	throttle.mux.RLock()
+	defer throttle.mux.RUnlock()
+
+	return throttle.max
+}
+
+// SetLimit changes max throughput / per second, it disables throttling if max is less than 1.
+func (throttle *ServerThrottle) SetLimit(max int) {
+	throttle.mux.Lock()
+	defer throttle.mux.Unlock()
+
+	if max < 1 {
+		throttle.max = 0
+		throttle.limiter = nil
+		return
+	}
+
+	burst := max * 20 / 100
+	if burst < 1 {
+		burst = 1
+	}
+
+	throttle.max = max
+	throttle.timeout = time.Second / time.Duration(max)
+	throttle.limiter = rate.NewLimiter(rate.Every(throttle.timeout), burst)
+}
+
+// Hook returns NamedHook of the throttle
+func (throttle *ServerThrottle) Hook() NamedHook {
+	return NamedHook{
+		Name:  "__server@throttle",
+		Apply: throttle.apply,
+	}
+}
+
+func (throttle *ServerThrottle) apply(w http.ResponseWriter, r *http.Request) bool {
+	throttle.mux.RLock()
+	limiter, timeout := throttle.limiter, throttle.timeout
+	throttle.mux.RUnlock()
+
+	if limiter == nil {
+		return true
+	}
+
+	ctx, done := context.WithTimeout(context.Background(), timeout)
+	err := limiter.Wait(ctx)
+	done()
+
+	if err != nil {
+		log.Println("Exceed throughput:", err)
+
+		w.Header().Set("Retry-After", time.Now().Add(timeout*3).Format(time.RFC3339))
+		http.Error(w, http.StatusText(http.StatusTeapot), http.StatusTeapot)
+		return false
+	}
+
+	return true
+}
+
+// ServerDemotion limits concurrency of requests within seconds.
+//
+// NOTE: tokens of bucket are refilled by max / seconds per second
+type ServerDemotion struct {
+	mux     sync.RWMutex
+	max     int
+	burst   int
+	timeout time.Duration
+	bucket  chan struct{}
+	changed chan struct{} // closed when bucket replaced, requests waiting should retry
+	done    chan struct{} // closed by Close for stopping refill
+}
+
+// NewServerDemotion creates *ServerDemotion with max concurrency within seconds.
+// It passes all requests if max is less than 1. Close should be called for
+// releasing the demotion.
+func NewServerDemotion(max, seconds int) *ServerDemotion {
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	demotion := &ServerDemotion{
+		timeout: time.Duration(seconds) * time.Second,
+		changed: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	demotion.SetLimit(max)
+
+	// impulse sender
+	go demotion.refill()
+
+	return demotion
+}
+
+// Limit returns max concurrency
+func (demotion *ServerDemotion) Limit() int {
+	demotion.mux.RLock()
+	defer demotion.mux.RUnlock()
+
+	return demotion.max
+}
+
+// SetLimit changes max concurrency, it disables demotion if max is less than 1.
+// Tokens left are kept within the new max, and the bucket is full if demotion
+// was disabled.
+//
+// NOTE: requests waiting for the old bucket wait for the new one.
+func (demotion *ServerDemotion) SetLimit(max int) {
+	demotion.mux.Lock()
+	defer demotion.mux.Unlock()
+
+	// take tokens left of the old bucket, it's never closed for requests waiting
+	tokens := max
+	if demotion.bucket != nil {
+		tokens = 0
+
+	drain:
+		for {
+			select {
+			case <-demotion.bucket:
+				tokens++
+
+			default:
+				break drain
+			}
+		}
+	}
+
+	// notify requests waiting for the old bucket
+	close(demotion.changed)
+	demotion.changed = make(chan struct{})
+
+	if max < 1 {
+		demotion.max = 0
+		demotion.burst = 0
+		demotion.bucket = nil
+		return
+	}
+
+	if tokens > max {
+		tokens = max
+	}
+
+	bucket := make(chan struct{}, max)
+	for i := 0; i < tokens; i++ {
+		bucket <- struct{}{}
+	}
+
+	burst := max / int(demotion.timeout/time.Second)
+	if burst < 1 {
+		burst = 1
+	}
+
+	demotion.max = max
+	demotion.burst = burst
+	demotion.bucket = bucket
+}
+
+// Close stops refilling tokens of bucket, it's safe to call multiple times.
+func (demotion *ServerDemotion) Close() {
+	demotion.mux.Lock()
+	defer demotion.mux.Unlock()
+
+	select {
+	case <-demotion.done:
+
+	default:
+		close(demotion.done)
+	}
+}
+
+// Hook returns NamedHook of the demotion
+func (demotion *ServerDemotion) Hook() NamedHook {
+	return NamedHook{
+		Name:  "__server@demotion",
+		Apply: demotion.apply,
+	}
+}
+
+func (demotion *ServerDemotion) apply(w http.ResponseWriter, r *http.Request) bool {
+	ticker := time.NewTicker(demotion.timeout)
+	defer ticker.Stop()
+
+	for {
+		demotion.mux.RLock()
+		bucket, changed := demotion.bucket, demotion.changed
+		demotion.mux.RUnlock()
+
+		if bucket == nil {
+			return true
+		}
+
+		select {
+		case <-bucket:
+			return true
+
+		case <-changed:
+			// retry with the new bucket
+
+		case <-ticker.C:
+			log.Println("Exceed concurrency:", demotion.timeout, "timeout")
+
+			ticker.Stop()
+
+			w.Header().Set("Retry-After", time.Now().Add(demotion.timeout).Format(time.RFC3339))
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+
+			return false
+		}
+	}
+}
+
+// refill puts burst tokens into bucket within every time.Second / burst until closed
+func (demotion *ServerDemotion) refill() {
+	timer := time.NewTimer(demotion.interval())
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-demotion.done:
+			return
+
+		case <-timer.C:
+		}
+
+		demotion.mux.RLock()
+		for i := 0; i < demotion.burst; i++ {
+			select {
+			case demotion.bucket <- struct{}{}:
+			default:
+			}
+		}
+		demotion.mux.RUnlock()
+
+		timer.Reset(demotion.interval())
+	}
+}
+
+func (demotion *ServerDemotion) interval() time.Duration {
+	demotion.mux.RLock()
+	defer demotion.mux.RUnlock()
+
+	if demotion.burst < 1 {
+		return time.Second
+	}
+
+	return time.Second / time.Duration(demotion.burst)
+}
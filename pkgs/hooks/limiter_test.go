@@ -0,0 +1,101 @@
+package hooks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/golib/assert"
+)
+
+func Test_ServerDemotionSetLimit(t *testing.T) {
+	it := assert.New(t)
+
+	demotion := NewServerDemotion(10, 10)
+	defer demotion.Close()
+
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	for i := 0; i < 8; i++ {
+		it.True(demotion.apply(httptest.NewRecorder(), r))
+	}
+
+	// it should keep tokens left within the new max
+	demotion.SetLimit(5)
+	it.Equal(5, demotion.Limit())
+	it.Equal(2, len(demotion.bucket))
+
+	demotion.SetLimit(1)
+	it.Equal(1, len(demotion.bucket))
+
+	demotion.SetLimit(20)
+	it.Equal(1, len(demotion.bucket))
+
+	// it should fill bucket after disabled
+	demotion.SetLimit(0)
+	it.Nil(demotion.bucket)
+
+	demotion.SetLimit(3)
+	it.Equal(3, len(demotion.bucket))
+}
+
+func Test_ServerDemotionSetLimitWithWaiting(t *testing.T) {
+	it := assert.New(t)
+
+	demotion := NewServerDemotion(1, 10)
+	defer demotion.Close()
+
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	it.True(demotion.apply(httptest.NewRecorder(), r))
+
+	passed := make(chan bool, 1)
+	go func() {
+		passed <- demotion.apply(httptest.NewRecorder(), r)
+	}()
+
+	// it should not pass requests waiting for the old bucket
+	time.Sleep(10 * time.Millisecond)
+	demotion.SetLimit(1)
+
+	select {
+	case <-passed:
+		t.Fatal("Expected waiting for tokens of the new bucket")
+
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// it should pass requests waiting after disabled
+	demotion.SetLimit(0)
+
+	select {
+	case ok := <-passed:
+		it.True(ok)
+
+	case <-time.After(time.Second):
+		t.Fatal("Expected passing after disabled")
+	}
+}
+
+func Test_ServerDemotionClose(t *testing.T) {
+	it := assert.New(t)
+
+	n := runtime.NumGoroutine()
+
+	demotions := make([]*ServerDemotion, 10)
+	for i := range demotions {
+		demotions[i] = NewServerDemotion(10, 1)
+	}
+	it.True(runtime.NumGoroutine() >= n+len(demotions))
+
+	// it should stop refilling
+	for _, demotion := range demotions {
+		demotion.Close()
+		demotion.Close()
+	}
+
+	for i := 0; i < 100 && runtime.NumGoroutine() > n; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	it.True(runtime.NumGoroutine() <= n)
+}
@@ -1,14 +1,5 @@
 package hooks
 
-import (
-	"context"
-	"log"
-	"net/http"
-	"time"
-
-	"golang.org/x/time/rate"
-)
-
 // A ServerHooks provides a collection of server hooks for various
 // stages of handling requests.
 type ServerHooks struct {
@@ -42,87 +33,20 @@ func NewServerHooks() *ServerHooks {
 // NewServerThrottleHook creates NamedHook with max throughput / per second.
 // NOTE: burst value is 20% of throttle
 func NewServerThrottleHook(max int) NamedHook {
-	burst := max * 20 / 100
-	if burst < 1 {
-		burst = 1
+	if max < 1 {
+		max = 1
 	}
 
-	timeout := time.Second / time.Duration(max)
-	limiter := rate.NewLimiter(rate.Every(timeout), burst)
-
-	return NamedHook{
-		Name: "__server@throttle",
-		Apply: func(w http.ResponseWriter, r *http.Request) bool {
-			ctx, done := context.WithTimeout(context.Background(), timeout)
-			err := limiter.Wait(ctx)
-			done()
-
-			if err != nil {
-				log.Println("Exceed throughput:", err)
-
-				w.Header().Set("Retry-After", time.Now().Add(timeout*3).Format(time.RFC3339))
-				http.Error(w, http.StatusText(http.StatusTeapot), http.StatusTeapot)
-				return false
-			}
-
-			return true
-		},
-	}
+	return NewServerThrottle(max).Hook()
 }
 
 // NewServerDemotionHook creates NamedHook with max concurrency within seconds.
-// NOTE: timeout after seconds
+// NOTE: timeout after seconds, and the demotion lives with process. Use
+// NewServerDemotion for closing.
 func NewServerDemotionHook(max, seconds int) NamedHook {
 	if max < 1 {
 		max = 1
 	}
-	if seconds < 1 {
-		seconds = 1
-	}
 
-	bucket := make(chan struct{}, max)
-	for i := 0; i < max; i++ {
-		bucket <- struct{}{}
-	}
-
-	timeout := time.Duration(seconds) * time.Second
-
-	// impulse sender
-	burst := max / seconds
-	go func() {
-		ticker := time.NewTicker(time.Second / time.Duration(burst))
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ticker.C:
-				for i := 0; i < burst; i++ {
-					bucket <- struct{}{}
-				}
-			}
-		}
-	}()
-
-	return NamedHook{
-		Name: "__server@demotion",
-		Apply: func(w http.ResponseWriter, r *http.Request) bool {
-			ticker := time.NewTicker(timeout)
-			defer ticker.Stop()
-
-			select {
-			case <-bucket:
-				return true
-
-			case <-ticker.C:
-				log.Println("Exceed concurrency:", timeout, "timeout")
-
-				ticker.Stop()
-
-				w.Header().Set("Retry-After", time.Now().Add(timeout).Format(time.RFC3339))
-				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
-
-				return false
-			}
-		},
-	}
+	return NewServerDemotion(max, seconds).Hook()
 }
@@ -0,0 +1,68 @@
+// Package identity represents client identity of mutual TLS for GOGO.
+package identity
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"net/http"
+)
+
+// An Identity defines identity of verified client certificate
+type Identity struct {
+	Subject        string   `json:"subject"`
+	CommonName     string   `json:"common_name"`
+	DNSNames       []string `json:"dns_names,omitempty"`
+	EmailAddresses []string `json:"email_addresses,omitempty"`
+	IPAddresses    []string `json:"ip_addresses,omitempty"`
+	URIs           []string `json:"uris,omitempty"`
+	Fingerprint    string   `json:"fingerprint"` // hex encoded sha256 of raw certificate
+}
+
+// New returns *Identity of certificate given
+func New(cert *x509.Certificate) *Identity {
+	sum := sha256.Sum256(cert.Raw)
+
+	id := &Identity{
+		Subject:        cert.Subject.String(),
+		CommonName:     cert.Subject.CommonName,
+		DNSNames:       cert.DNSNames,
+		EmailAddresses: cert.EmailAddresses,
+		Fingerprint:    hex.EncodeToString(sum[:]),
+	}
+
+	for _, ip := range cert.IPAddresses {
+		id.IPAddresses = append(id.IPAddresses, ip.String())
+	}
+
+	for _, uri := range cert.URIs {
+		id.URIs = append(id.URIs, uri.String())
+	}
+
+	return id
+}
+
+// FromRequest returns *Identity of verified client certificate of request,
+// it returns nil if there is no verified client certificate.
+func FromRequest(r *http.Request) *Identity {
+	if r == nil || r.TLS == nil {
+		return nil
+	}
+
+	if len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+
+	return New(r.TLS.VerifiedChains[0][0])
+}
+
+// SANs returns all subject alternative names of the identity
+func (id *Identity) SANs() []string {
+	sans := make([]string, 0, len(id.DNSNames)+len(id.EmailAddresses)+len(id.IPAddresses)+len(id.URIs))
+	sans = append(sans, id.DNSNames...)
+	sans = append(sans, id.EmailAddresses...)
+	sans = append(sans, id.IPAddresses...)
+	sans = append(sans, id.URIs...)
+
+	return sans
+}
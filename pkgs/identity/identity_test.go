@@ -0,0 +1,63 @@
+package identity
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/golib/assert"
+)
+
+var (
+	fakeCertificate = func() *x509.Certificate {
+		root, _ := os.Getwd()
+		dir := path.Join(root, "..", "..", "testdata", "ssl")
+
+		cert, err := tls.LoadX509KeyPair(path.Join(dir, "client.crt"), path.Join(dir, "client.key"))
+		if err != nil {
+			panic(err)
+		}
+
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			panic(err)
+		}
+
+		return leaf
+	}
+)
+
+func Test_New(t *testing.T) {
+	it := assert.New(t)
+
+	id := New(fakeCertificate())
+	it.Equal("CN=client.svc.local", id.Subject)
+	it.Equal("client.svc.local", id.CommonName)
+	it.Equal([]string{"client.svc.local"}, id.DNSNames)
+	it.Equal([]string{"spiffe://gogo/client"}, id.URIs)
+	it.Len(id.Fingerprint, 64)
+	it.Equal([]string{"client.svc.local", "spiffe://gogo/client"}, id.SANs())
+}
+
+func Test_FromRequest(t *testing.T) {
+	it := assert.New(t)
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	it.Nil(FromRequest(r))
+
+	// it should return nil without verified chains
+	r.TLS = &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{fakeCertificate()},
+	}
+	it.Nil(FromRequest(r))
+
+	r.TLS.VerifiedChains = [][]*x509.Certificate{r.TLS.PeerCertificates}
+
+	id := FromRequest(r)
+	if it.NotNil(id) {
+		it.Equal("client.svc.local", id.CommonName)
+	}
+}
@@ -83,7 +83,7 @@ func (d *Debugger) Reload(unmarshaler interceptors.Configer) (err error) {
 	return unmarshaler.Unmarshal(d.Name(), &d.config)
 }
 
-// Shutdown will do nothing
+// Shutdown implements interceptors.Shutdowner, it does nothing
 func (d *Debugger) Shutdown() (err error) {
 	return
 }
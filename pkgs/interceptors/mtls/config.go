@@ -0,0 +1,40 @@
+package mtls
+
+import (
+	"path"
+)
+
+// A Config defines user custom settings of mtls
+type Config struct {
+	Priority     int      `yaml:"priority"`
+	SANs         []string `yaml:"sans"`         // allowed subject alternative names, wildcard is supported, e.g. *.svc.local
+	Fingerprints []string `yaml:"fingerprints"` // allowed sha256 fingerprints in hex
+}
+
+// Authorizable returns true if there is any rule defined
+func (c *Config) Authorizable() bool {
+	if c == nil {
+		return false
+	}
+
+	return len(c.SANs) > 0 || len(c.Fingerprints) > 0
+}
+
+// Allow returns true if any of sans or fingerprint given matched
+func (c *Config) Allow(sans []string, fingerprint string) bool {
+	for _, allowed := range c.Fingerprints {
+		if allowed == fingerprint {
+			return true
+		}
+	}
+
+	for _, pattern := range c.SANs {
+		for _, san := range sans {
+			if ok, _ := path.Match(pattern, san); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,25 @@
+package mtls
+
+import (
+	"testing"
+
+	"github.com/golib/assert"
+)
+
+func Test_Config(t *testing.T) {
+	it := assert.New(t)
+
+	var config *Config
+	it.False(config.Authorizable())
+
+	config = &Config{
+		SANs:         []string{"*.svc.local", "spiffe://gogo/*"},
+		Fingerprints: []string{"fingerprint"},
+	}
+	it.True(config.Authorizable())
+
+	it.True(config.Allow([]string{"client.svc.local"}, ""))
+	it.True(config.Allow([]string{"spiffe://gogo/client"}, ""))
+	it.True(config.Allow(nil, "fingerprint"))
+	it.False(config.Allow([]string{"client.svc.remote"}, "unknown"))
+}
@@ -0,0 +1,143 @@
+package mtls
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/dolab/gogo/pkgs/identity"
+	"github.com/dolab/gogo/pkgs/interceptors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// internalPrefix defines prefix of internal routes of server, e.g. /-/healthz,
+// which are served without client certificates.
+const internalPrefix = "/-/"
+
+// A Authorizer implements interceptors.Interface, it authorizes requests by
+// client identity of mutual TLS.
+type Authorizer struct {
+	name string
+	log  interceptors.Logger
+
+	mux    sync.RWMutex
+	config *Config
+}
+
+// New creates *Authorizer
+func New() *Authorizer {
+	return &Authorizer{
+		name: "mtls",
+	}
+}
+
+// Name returns name of mtls
+func (a *Authorizer) Name() string {
+	return a.name
+}
+
+// Config returns settings template of mtls
+func (a *Authorizer) Config() []byte {
+	b, _ := yaml.Marshal(Config{})
+
+	return b
+}
+
+// SetLogger implements interceptors.Logable
+func (a *Authorizer) SetLogger(log interceptors.Logger) {
+	a.mux.Lock()
+	a.log = log
+	a.mux.Unlock()
+}
+
+// Priority returns sort order of mtls
+func (a *Authorizer) Priority() int {
+	a.mux.RLock()
+	defer a.mux.RUnlock()
+
+	return a.config.Priority
+}
+
+// Register unmarshals config of mtls and return
+func (a *Authorizer) Register(unmarshaler interceptors.Configer) (callee interceptors.Interceptor, err error) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	if unmarshaler == nil {
+		err = errors.New("invalid unmarshaler")
+		return
+	}
+
+	var config *Config
+
+	err = unmarshaler.Unmarshal(a.Name(), &config)
+	if err != nil {
+		return
+	}
+
+	if !config.Authorizable() {
+		err = errors.New("no mtls")
+		return
+	}
+
+	a.config = config
+	callee = a.interceptor
+
+	return
+}
+
+// Reload tries to update settings of mtls at fly, it keeps the current if the
+// new is invalid.
+func (a *Authorizer) Reload(unmarshaler interceptors.Configer) (err error) {
+	if unmarshaler == nil {
+		return errors.New("invalid unmarshaler")
+	}
+
+	var config *Config
+
+	err = unmarshaler.Unmarshal(a.Name(), &config)
+	if err != nil {
+		return
+	}
+
+	if !config.Authorizable() {
+		return errors.New("no mtls")
+	}
+
+	a.mux.Lock()
+	a.config = config
+	a.mux.Unlock()
+
+	return
+}
+
+// interceptor authorizes requests of TLS listeners, requests of plaintext
+// listeners and internal routes are skipped.
+func (a *Authorizer) interceptor(w http.ResponseWriter, r *http.Request) bool {
+	if r.TLS == nil || strings.HasPrefix(r.URL.Path, internalPrefix) {
+		return true
+	}
+
+	id := identity.FromRequest(r)
+	if id == nil {
+		http.Error(w, "Client certificate required", http.StatusUnauthorized)
+		return false
+	}
+
+	a.mux.RLock()
+	allowed := a.config.Allow(id.SANs(), id.Fingerprint)
+	log := a.log
+	a.mux.RUnlock()
+
+	if !allowed {
+		if log != nil {
+			log.Warnf("Forbidden client certificate: %s (%s)", id.Subject, id.Fingerprint)
+		}
+
+		http.Error(w, "Client certificate forbidden", http.StatusForbidden)
+		return false
+	}
+
+	return true
+}
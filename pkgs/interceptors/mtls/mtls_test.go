@@ -0,0 +1,79 @@
+package mtls
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golib/assert"
+	yaml "gopkg.in/yaml.v2"
+)
+
+type fakeConfiger map[string]string
+
+func (c fakeConfiger) Unmarshal(name string, v interface{}) error {
+	return yaml.Unmarshal([]byte(c[name]), v)
+}
+
+func Test_AuthorizerReload(t *testing.T) {
+	it := assert.New(t)
+
+	authorizer := New()
+
+	_, err := authorizer.Register(fakeConfiger{
+		"mtls": "sans: ['*.svc.local']",
+	})
+	if !it.Nil(err) {
+		return
+	}
+
+	// it should keep the current with null or removed section
+	for _, config := range []fakeConfiger{{"mtls": "null"}, {}} {
+		err = authorizer.Reload(config)
+		it.NotNil(err)
+		it.Equal([]string{"*.svc.local"}, authorizer.config.SANs)
+	}
+
+	err = authorizer.Reload(fakeConfiger{
+		"mtls": "fingerprints: [fingerprint]",
+	})
+	if it.Nil(err) {
+		it.Empty(authorizer.config.SANs)
+		it.Equal([]string{"fingerprint"}, authorizer.config.Fingerprints)
+	}
+}
+
+func Test_AuthorizerInterceptor(t *testing.T) {
+	it := assert.New(t)
+
+	authorizer := New()
+
+	interceptor, err := authorizer.Register(fakeConfiger{
+		"mtls": "sans: ['*.svc.local']",
+	})
+	if !it.Nil(err) {
+		return
+	}
+
+	// it should skip requests of plaintext listeners
+	r, _ := http.NewRequest(http.MethodGet, "/mtls", nil)
+	w := httptest.NewRecorder()
+
+	it.True(interceptor(w, r))
+
+	// it should skip internal routes
+	r, _ = http.NewRequest(http.MethodGet, "/-/healthz", nil)
+	r.TLS = &tls.ConnectionState{}
+	w = httptest.NewRecorder()
+
+	it.True(interceptor(w, r))
+
+	// it should require client certificate for TLS listeners
+	r, _ = http.NewRequest(http.MethodGet, "/mtls", nil)
+	r.TLS = &tls.ConnectionState{}
+	w = httptest.NewRecorder()
+
+	it.False(interceptor(w, r))
+	it.Equal(http.StatusUnauthorized, w.Code)
+}
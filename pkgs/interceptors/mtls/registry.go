@@ -0,0 +1,23 @@
+package mtls
+
+import (
+	"github.com/dolab/gogo/pkgs/interceptors"
+)
+
+// A Registry defines interceptors for mtls authorization
+type Registry struct {
+	requestReceived []interceptors.Interface
+}
+
+// NewRegistry creates *Registry with mtls authorizer at request received phase
+func NewRegistry() *Registry {
+	return &Registry{
+		requestReceived: []interceptors.Interface{
+			New(),
+		},
+	}
+}
+
+func (reg *Registry) RequestReceived() []interceptors.Interface {
+	return reg.requestReceived
+}
@@ -30,6 +30,24 @@ type Interface interface {
 	Reload(Configer) error
 }
 
+// A Logger defines log interface of server for interceptors
+type Logger interface {
+	Warnf(format string, v ...interface{})
+	Errorf(format string, v ...interface{})
+}
+
+// A Logable defines interceptors which log with logger of server. It's called
+// before Register.
+type Logable interface {
+	SetLogger(Logger)
+}
+
+// A Shutdowner defines interceptors which release resources when server shutdown.
+// It's called after all connections drained.
+type Shutdowner interface {
+	Shutdown() error
+}
+
 // A RequestReceivedInterceptor represents request received interface of server
 type RequestReceivedInterceptor interface {
 	RequestReceived() []Interface
@@ -0,0 +1,70 @@
+package metadata
+
+import (
+	"context"
+	"net/http"
+)
+
+type contextKey int
+
+const (
+	ctxMetadataKey contextKey = iota + 1
+)
+
+// Metadata defines annotations of a route attached at registration, e.g. auth
+// scopes, rate-limit class, deprecation flag and owner team. It's read only
+// while serving, and shared by all requests of the route.
+type Metadata map[string]interface{}
+
+// Get returns value of key, ok is false if the key is not present.
+func (md Metadata) Get(key string) (value interface{}, ok bool) {
+	value, ok = md[key]
+	return
+}
+
+// String returns value of key if it's a string, it returns empty otherwise.
+func (md Metadata) String(key string) string {
+	s, _ := md[key].(string)
+
+	return s
+}
+
+// Strings returns value of key if it's a []string, it returns nil otherwise.
+func (md Metadata) Strings(key string) []string {
+	values, _ := md[key].([]string)
+
+	return values
+}
+
+// Bool returns value of key if it's a bool, it returns false otherwise.
+func (md Metadata) Bool(key string) bool {
+	b, _ := md[key].(bool)
+
+	return b
+}
+
+// Int returns value of key if it's an int, it returns zero otherwise.
+func (md Metadata) Int(key string) int {
+	i, _ := md[key].(int)
+
+	return i
+}
+
+// NewContext returns a new context.Context carrying the Metadata
+func NewContext(ctx context.Context, md Metadata) context.Context {
+	return context.WithValue(ctx, ctxMetadataKey, md)
+}
+
+// FromContext returns Metadata stored in ctx, it returns nil if there is no
+// metadata attached with the route.
+func FromContext(ctx context.Context) Metadata {
+	md, _ := ctx.Value(ctxMetadataKey).(Metadata)
+
+	return md
+}
+
+// FromRequest returns Metadata of the route which request routed to. It's
+// useful for hooks and interceptors.
+func FromRequest(r *http.Request) Metadata {
+	return FromContext(r.Context())
+}
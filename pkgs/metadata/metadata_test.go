@@ -0,0 +1,53 @@
+package metadata
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/golib/assert"
+)
+
+func Test_Metadata(t *testing.T) {
+	it := assert.New(t)
+
+	md := Metadata{
+		"owner":      "payment",
+		"scopes":     []string{"orders:read"},
+		"deprecated": true,
+		"class":      2,
+	}
+
+	value, ok := md.Get("owner")
+	it.True(ok)
+	it.Equal("payment", value)
+	it.Equal("payment", md.String("owner"))
+	it.Equal([]string{"orders:read"}, md.Strings("scopes"))
+	it.True(md.Bool("deprecated"))
+	it.Equal(2, md.Int("class"))
+
+	// it should work with mismatched type
+	it.Empty(md.String("class"))
+	it.Nil(md.Strings("owner"))
+	it.False(md.Bool("owner"))
+	it.Zero(md.Int("owner"))
+
+	// it should work with nil
+	var nilMD Metadata
+
+	_, ok = nilMD.Get("owner")
+	it.False(ok)
+	it.Empty(nilMD.String("owner"))
+}
+
+func Test_FromRequest(t *testing.T) {
+	it := assert.New(t)
+
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	it.Nil(FromRequest(r))
+
+	md := Metadata{"owner": "payment"}
+
+	r = r.WithContext(NewContext(context.Background(), md))
+	it.Equal(md, FromRequest(r))
+}
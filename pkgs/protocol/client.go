@@ -0,0 +1,24 @@
+package protocol
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+
+	"golang.org/x/net/http2"
+)
+
+// NewH2CClient returns *http.Client which speaks HTTP/2 over plaintext with prior
+// knowledge, it's useful for generated clients of services served with h2c.
+//
+// NOTE: All requests share multiplexed connections for the same host.
+func NewH2CClient() *http.Client {
+	return &http.Client{
+		Transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+				return net.Dial(network, addr)
+			},
+		},
+	}
+}
@@ -0,0 +1,31 @@
+package proxyproto
+
+import (
+	"context"
+	"net/http"
+)
+
+type contextKey int
+
+const (
+	ctxHeaderKey contextKey = iota + 1
+)
+
+// NewContext returns a new context.Context carrying the *Header
+func NewContext(ctx context.Context, header *Header) context.Context {
+	return context.WithValue(ctx, ctxHeaderKey, header)
+}
+
+// FromContext returns *Header stored in ctx, it returns nil if there is no
+// PROXY header received for the connection.
+func FromContext(ctx context.Context) *Header {
+	header, _ := ctx.Value(ctxHeaderKey).(*Header)
+
+	return header
+}
+
+// FromRequest returns *Header of the connection which request received from.
+// It's useful for hooks and middlewares.
+func FromRequest(r *http.Request) *Header {
+	return FromContext(r.Context())
+}
@@ -0,0 +1,307 @@
+// Package proxyproto implements parsing of HAProxy PROXY protocol headers for GOGO.
+// Both v1 text header and v2 binary header are supported.
+//
+// See https://www.haproxy.org/download/2.0/doc/proxy-protocol.txt for details.
+package proxyproto
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// errors
+var (
+	ErrNoHeader      = errors.New("proxyproto: no PROXY header")
+	ErrInvalidHeader = errors.New("proxyproto: invalid PROXY header")
+)
+
+// types of TLV defined by v2
+const (
+	TypeALPN      byte = 0x01
+	TypeAuthority byte = 0x02 // host name of client, e.g. SNI of tls
+	TypeCRC32C    byte = 0x03
+	TypeNoop      byte = 0x04
+	TypeUniqueID  byte = 0x05
+	TypeSSL       byte = 0x20
+	TypeNetNS     byte = 0x30
+)
+
+const (
+	v1Prefix    = "PROXY "
+	v1MaxLength = 107
+)
+
+var (
+	v2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+)
+
+// A TLV defines type-length-value of v2 header
+type TLV struct {
+	Type  byte
+	Value []byte
+}
+
+// A Header defines PROXY header sent by proxies
+type Header struct {
+	Version     int      // 1 or 2
+	Local       bool     // true for LOCAL command of v2, e.g. health checks of proxies
+	Source      net.Addr // nil for UNKNOWN of v1 and LOCAL of v2
+	Destination net.Addr // nil for UNKNOWN of v1 and LOCAL of v2
+	TLVs        []TLV    // v2 only
+}
+
+// TLV returns value of the first TLV matched the type
+func (h *Header) TLV(typ byte) ([]byte, bool) {
+	for _, tlv := range h.TLVs {
+		if tlv.Type == typ {
+			return tlv.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// Authority returns value of TypeAuthority TLV, it's SNI of client in general.
+func (h *Header) Authority() string {
+	value, _ := h.TLV(TypeAuthority)
+
+	return string(value)
+}
+
+// Read parses PROXY header from r. It returns ErrNoHeader without consuming
+// any data if r does not start with a PROXY header.
+func Read(r *bufio.Reader) (*Header, error) {
+	b, err := r.Peek(1)
+	if err != nil {
+		return nil, err
+	}
+
+	switch b[0] {
+	case v1Prefix[0]:
+		b, err = r.Peek(len(v1Prefix))
+		if err != nil || string(b) != v1Prefix {
+			return nil, ErrNoHeader
+		}
+
+		return readV1(r)
+
+	case v2Signature[0]:
+		b, err = r.Peek(len(v2Signature))
+		if err != nil || !bytes.Equal(b, v2Signature) {
+			return nil, ErrNoHeader
+		}
+
+		return readV2(r)
+	}
+
+	return nil, ErrNoHeader
+}
+
+// readV1 parses header like PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\n
+func readV1(r *bufio.Reader) (*Header, error) {
+	line := make([]byte, 0, v1MaxLength)
+	for {
+		c, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		line = append(line, c)
+		if c == '\n' {
+			break
+		}
+
+		if len(line) >= v1MaxLength {
+			return nil, ErrInvalidHeader
+		}
+	}
+
+	if !bytes.HasSuffix(line, []byte("\r\n")) {
+		return nil, ErrInvalidHeader
+	}
+
+	fields := strings.Split(string(line[len(v1Prefix):len(line)-2]), " ")
+
+	header := &Header{
+		Version: 1,
+	}
+
+	switch fields[0] {
+	case "UNKNOWN":
+		return header, nil
+
+	case "TCP4", "TCP6":
+		if len(fields) != 5 {
+			return nil, ErrInvalidHeader
+		}
+
+	default:
+		return nil, ErrInvalidHeader
+	}
+
+	src, err := parseV1Addr(fields[0], fields[1], fields[3])
+	if err != nil {
+		return nil, err
+	}
+
+	dst, err := parseV1Addr(fields[0], fields[2], fields[4])
+	if err != nil {
+		return nil, err
+	}
+
+	header.Source = src
+	header.Destination = dst
+
+	return header, nil
+}
+
+func parseV1Addr(proto, host, port string) (*net.TCPAddr, error) {
+	ip := net.ParseIP(host)
+	if ip == nil || (proto == "TCP4") != (ip.To4() != nil) {
+		return nil, ErrInvalidHeader
+	}
+
+	n, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return nil, ErrInvalidHeader
+	}
+
+	return &net.TCPAddr{
+		IP:   ip,
+		Port: int(n),
+	}, nil
+}
+
+// readV2 parses binary header with signature, version/command, family and length
+func readV2(r *bufio.Reader) (*Header, error) {
+	prefix := make([]byte, len(v2Signature)+4)
+
+	_, err := io.ReadFull(r, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		version  = prefix[12] >> 4
+		command  = prefix[12] & 0x0f
+		family   = prefix[13] >> 4
+		protocol = prefix[13] & 0x0f
+		length   = binary.BigEndian.Uint16(prefix[14:])
+	)
+	if version != 2 || command > 1 {
+		return nil, ErrInvalidHeader
+	}
+
+	data := make([]byte, length)
+
+	_, err = io.ReadFull(r, data)
+	if err != nil {
+		return nil, err
+	}
+
+	header := &Header{
+		Version: 2,
+		Local:   command == 0,
+	}
+
+	// the receiver must ignore address information of LOCAL command
+	if header.Local {
+		return header, nil
+	}
+
+	var size int
+	switch family {
+	case 0x0: // AF_UNSPEC
+		size = 0
+
+	case 0x1: // AF_INET
+		size = 12
+		if len(data) < size {
+			return nil, ErrInvalidHeader
+		}
+
+		header.Source = newV2Addr(protocol, net.IP(data[0:4]), data[8:10])
+		header.Destination = newV2Addr(protocol, net.IP(data[4:8]), data[10:12])
+
+	case 0x2: // AF_INET6
+		size = 36
+		if len(data) < size {
+			return nil, ErrInvalidHeader
+		}
+
+		header.Source = newV2Addr(protocol, net.IP(data[0:16]), data[32:34])
+		header.Destination = newV2Addr(protocol, net.IP(data[16:32]), data[34:36])
+
+	case 0x3: // AF_UNIX
+		size = 216
+		if len(data) < size {
+			return nil, ErrInvalidHeader
+		}
+
+		network := "unix"
+		if protocol == 0x2 {
+			network = "unixgram"
+		}
+
+		header.Source = &net.UnixAddr{Name: unixName(data[0:108]), Net: network}
+		header.Destination = &net.UnixAddr{Name: unixName(data[108:216]), Net: network}
+
+	default:
+		return nil, ErrInvalidHeader
+	}
+
+	header.TLVs, err = parseTLVs(data[size:])
+	if err != nil {
+		return nil, err
+	}
+
+	return header, nil
+}
+
+func newV2Addr(protocol byte, ip net.IP, port []byte) net.Addr {
+	n := int(binary.BigEndian.Uint16(port))
+
+	if protocol == 0x2 {
+		return &net.UDPAddr{IP: ip, Port: n}
+	}
+
+	return &net.TCPAddr{IP: ip, Port: n}
+}
+
+func unixName(data []byte) string {
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		data = data[:i]
+	}
+
+	return string(data)
+}
+
+func parseTLVs(data []byte) (tlvs []TLV, err error) {
+	for len(data) > 0 {
+		if len(data) < 3 {
+			return nil, ErrInvalidHeader
+		}
+
+		size := int(binary.BigEndian.Uint16(data[1:3]))
+		if len(data) < 3+size {
+			return nil, ErrInvalidHeader
+		}
+
+		if data[0] != TypeNoop {
+			tlvs = append(tlvs, TLV{
+				Type:  data[0],
+				Value: data[3 : 3+size],
+			})
+		}
+
+		data = data[3+size:]
+	}
+
+	return
+}
@@ -0,0 +1,136 @@
+package proxyproto
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/golib/assert"
+)
+
+func Test_ReadV1(t *testing.T) {
+	it := assert.New(t)
+
+	reader := bufio.NewReader(strings.NewReader("PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\nGET / HTTP/1.1\r\n"))
+
+	header, err := Read(reader)
+	if it.Nil(err) {
+		it.Equal(1, header.Version)
+		it.Equal("192.168.0.1:56324", header.Source.String())
+		it.Equal("192.168.0.11:443", header.Destination.String())
+	}
+
+	// it should keep data after header
+	data, _ := ioutil.ReadAll(reader)
+	it.Equal("GET / HTTP/1.1\r\n", string(data))
+}
+
+func Test_ReadV1WithUnknown(t *testing.T) {
+	it := assert.New(t)
+
+	header, err := Read(bufio.NewReader(strings.NewReader("PROXY UNKNOWN\r\n")))
+	if it.Nil(err) {
+		it.Nil(header.Source)
+		it.Nil(header.Destination)
+	}
+}
+
+func Test_ReadV1WithInvalid(t *testing.T) {
+	it := assert.New(t)
+
+	testCases := []string{
+		"PROXY TCP4 192.168.0.1 192.168.0.11 56324\r\n",
+		"PROXY TCP4 ::1 192.168.0.11 56324 443\r\n",
+		"PROXY TCP6 ::1 ::1 56324 65536\r\n",
+		"PROXY UDP4 192.168.0.1 192.168.0.11 56324 443\r\n",
+		"PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\n",
+	}
+
+	for _, testCase := range testCases {
+		_, err := Read(bufio.NewReader(strings.NewReader(testCase)))
+		it.Equal(ErrInvalidHeader, err, testCase)
+	}
+}
+
+func Test_ReadV2(t *testing.T) {
+	it := assert.New(t)
+
+	var buf bytes.Buffer
+	buf.Write(v2Signature)
+	buf.Write([]byte{0x21, 0x11}) // PROXY, TCP over IPv4
+
+	tlv := append([]byte{TypeAuthority, 0, 11}, "example.com"...)
+	addr := []byte{10, 0, 0, 1, 10, 0, 0, 2, 0xdc, 0x04, 0x01, 0xbb}
+
+	binary.Write(&buf, binary.BigEndian, uint16(len(addr)+len(tlv)))
+	buf.Write(addr)
+	buf.Write(tlv)
+	buf.WriteString("GET / HTTP/1.1\r\n")
+
+	reader := bufio.NewReader(&buf)
+
+	header, err := Read(reader)
+	if it.Nil(err) {
+		it.Equal(2, header.Version)
+		it.False(header.Local)
+		it.Equal(&net.TCPAddr{IP: net.IP{10, 0, 0, 1}, Port: 56324}, header.Source)
+		it.Equal(&net.TCPAddr{IP: net.IP{10, 0, 0, 2}, Port: 443}, header.Destination)
+		it.Equal("example.com", header.Authority())
+
+		_, ok := header.TLV(TypeALPN)
+		it.False(ok)
+	}
+
+	data, _ := ioutil.ReadAll(reader)
+	it.Equal("GET / HTTP/1.1\r\n", string(data))
+}
+
+func Test_ReadV2WithLocal(t *testing.T) {
+	it := assert.New(t)
+
+	var buf bytes.Buffer
+	buf.Write(v2Signature)
+	buf.Write([]byte{0x20, 0x00, 0x00, 0x00})
+
+	header, err := Read(bufio.NewReader(&buf))
+	if it.Nil(err) {
+		it.True(header.Local)
+		it.Nil(header.Source)
+	}
+}
+
+func Test_ReadV2WithInvalid(t *testing.T) {
+	it := assert.New(t)
+
+	testCases := map[string][]byte{
+		"version":   {0x11, 0x11, 0x00, 0x0c},
+		"address":   {0x21, 0x11, 0x00, 0x04, 10, 0, 0, 1},
+		"tlv":       {0x21, 0x11, 0x00, 0x0e, 10, 0, 0, 1, 10, 0, 0, 2, 0, 1, 0, 2, 0x02, 0x00},
+		"family":    {0x21, 0x41, 0x00, 0x00},
+		"truncated": {0x21, 0x11, 0x00, 0x0c, 10, 0, 0, 1},
+	}
+
+	for name, testCase := range testCases {
+		_, err := Read(bufio.NewReader(bytes.NewReader(append(v2Signature[:12:12], testCase...))))
+		it.NotNil(err, name)
+	}
+}
+
+func Test_ReadWithoutHeader(t *testing.T) {
+	it := assert.New(t)
+
+	for _, testCase := range []string{"GET / HTTP/1.1\r\n", "POST / HTTP/1.1\r\n", "\r\n\r\n"} {
+		reader := bufio.NewReader(strings.NewReader(testCase))
+
+		_, err := Read(reader)
+		it.Equal(ErrNoHeader, err)
+
+		// it should not consume any data
+		data, _ := ioutil.ReadAll(reader)
+		it.Equal(testCase, string(data))
+	}
+}
@@ -0,0 +1,168 @@
+package gogo
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dolab/gogo/internal/watcher"
+	"github.com/dolab/gogo/pkgs/interceptors"
+)
+
+// status of interceptors reloading
+const (
+	ReloadStatusOK    = "ok"
+	ReloadStatusFail  = "fail"
+	ReloadStatusNever = "never"
+)
+
+// A ReloadStatus defines result of the last reloading of interceptors
+type ReloadStatus struct {
+	Filename     string    `json:"filename"`
+	Status       string    `json:"status"`
+	Error        string    `json:"error,omitempty"`
+	Interceptors []string  `json:"interceptors"` // names of interceptors reloaded
+	ReloadedAt   time.Time `json:"reloaded_at"`
+}
+
+// interceptorReloader reloads all interceptors registered with config of interceptors.
+type interceptorReloader struct {
+	mux    sync.Mutex
+	ifaces []interceptors.Interface
+	status *ReloadStatus
+}
+
+// add registers interceptor for reloading, it ignores registered.
+func (r *interceptorReloader) add(m interceptors.Interface) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	for _, iface := range r.ifaces {
+		if iface == m {
+			return
+		}
+	}
+
+	r.ifaces = append(r.ifaces, m)
+}
+
+// reload re-parses config of interceptors and calls Reload of all interceptors
+// in order of registration. It rolls back both of the config and interceptors
+// reloaded if anyone failed.
+func (r *interceptorReloader) reload(config ReloadableConfiger) (status *ReloadStatus, err error) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	status = &ReloadStatus{
+		Filename:   config.InterceptorsFilename(),
+		Status:     ReloadStatusOK,
+		ReloadedAt: time.Now(),
+	}
+
+	defer func() {
+		if err != nil {
+			status.Status = ReloadStatusFail
+			status.Error = err.Error()
+		}
+
+		r.status = status
+	}()
+
+	prev := config.Interceptors()
+
+	err = config.LoadInterceptors()
+	if err != nil {
+		config.SetInterceptors(prev)
+		return
+	}
+
+	next := config.Interceptors()
+	for i, iface := range r.ifaces {
+		err = iface.Reload(next)
+		if err == nil {
+			status.Interceptors = append(status.Interceptors, iface.Name())
+			continue
+		}
+
+		err = fmt.Errorf("%s.Reload(): %v", iface.Name(), err)
+
+		// roll back in reverse order, including the failed one
+		for j := i; j >= 0; j-- {
+			r.ifaces[j].Reload(prev)
+		}
+
+		config.SetInterceptors(prev)
+		status.Interceptors = nil
+		return
+	}
+
+	return
+}
+
+// last returns a copy of the last reloading status, it returns nil if never reloaded.
+func (r *interceptorReloader) last() *ReloadStatus {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if r.status == nil {
+		return nil
+	}
+
+	status := *r.status
+	return &status
+}
+
+// ReloadInterceptors re-parses config of interceptors and reloads all interceptors
+// registered. It keeps the old config and interceptors if failed.
+//
+// NOTE: It does nothing if config of server does not implement ReloadableConfiger.
+func (s *AppServer) ReloadInterceptors() error {
+	config, ok := s.config.(ReloadableConfiger)
+	if !ok || config.InterceptorsFilename() == "" {
+		return nil
+	}
+
+	log := s.loggerNew("GOGO")
+
+	status, err := s.localReloader.reload(config)
+	if err != nil {
+		log.Errorf("Reload interceptors of %s: %v, roll back", status.Filename, err)
+		return err
+	}
+
+	log.Infof("Reloaded interceptors of %s", status.Filename)
+	return nil
+}
+
+// ReloadStatus returns status of the last reloading of interceptors, it returns
+// nil if never reloaded.
+func (s *AppServer) ReloadStatus() *ReloadStatus {
+	return s.localReloader.last()
+}
+
+// interceptorsFilename returns file of interceptors if config of server supports
+// reloading, it returns empty string otherwise.
+func (s *AppServer) interceptorsFilename() string {
+	config, ok := s.config.(ReloadableConfiger)
+	if !ok {
+		return ""
+	}
+
+	return config.InterceptorsFilename()
+}
+
+// watchInterceptors starts checking changes of interceptors file within interval,
+// and reloads all interceptors if changed.
+func (s *AppServer) watchInterceptors(interval time.Duration) {
+	filename := s.interceptorsFilename()
+	if filename == "" {
+		return
+	}
+
+	w := watcher.New(interval, func() {
+		s.ReloadInterceptors()
+	}, filename)
+	w.Start()
+
+	s.RegisterOnShutdown(w.Stop)
+}
@@ -0,0 +1,242 @@
+package gogo
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dolab/gogo/pkgs/interceptors"
+	"github.com/golib/assert"
+)
+
+type stubReloadInterceptor struct {
+	*stubInterceptor
+
+	mux   sync.Mutex
+	value string
+}
+
+func (stub *stubReloadInterceptor) Reload(config interceptors.Configer) error {
+	var data struct {
+		Value string `yaml:"value"`
+	}
+
+	err := config.Unmarshal(stub.name, &data)
+	if err != nil {
+		return err
+	}
+	if data.Value == "invalid" {
+		return errors.New("invalid value")
+	}
+
+	stub.mux.Lock()
+	stub.value = data.Value
+	stub.mux.Unlock()
+
+	return nil
+}
+
+func (stub *stubReloadInterceptor) Value() string {
+	stub.mux.Lock()
+	defer stub.mux.Unlock()
+
+	return stub.value
+}
+
+func fakeReloadServer(t *testing.T) (server *AppServer, filename string, cleanup func()) {
+	root, err := ioutil.TempDir("", "gogo-reload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.MkdirAll(path.Join(root, "config"), 0755)
+
+	data, _ := ioutil.ReadFile(path.Join("testdata", "config", "application.yml"))
+	ioutil.WriteFile(path.Join(root, "config", "application.yml"), data, 0644)
+
+	filename = path.Join(root, "config", "interceptors.yml")
+	ioutil.WriteFile(filename, []byte("first:\n  value: v1\nsecond:\n  value: v1\n"), 0644)
+
+	config, err := NewAppConfig(path.Join(root, "config", "application.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.LoadInterceptors()
+
+	server = NewAppServer(config, NewAppLogger("nil", ""))
+	cleanup = func() {
+		os.RemoveAll(root)
+	}
+
+	return
+}
+
+func Test_ServerReloadInterceptors(t *testing.T) {
+	it := assert.New(t)
+
+	server, filename, cleanup := fakeReloadServer(t)
+	defer cleanup()
+
+	apply := func(w http.ResponseWriter, r *http.Request) bool {
+		return true
+	}
+	first := &stubReloadInterceptor{stubInterceptor: &stubInterceptor{name: "first", apply: apply}}
+	second := &stubReloadInterceptor{stubInterceptor: &stubInterceptor{name: "second", apply: apply}}
+
+	it.Nil(server.WithRequestReceived(first))
+	it.Nil(server.WithResponseAlways(first))
+	it.Nil(server.WithRequestRouted(second))
+	it.Nil(server.ReloadStatus())
+
+	// it should reload all interceptors once
+	ioutil.WriteFile(filename, []byte("first:\n  value: v2\nsecond:\n  value: v2\n"), 0644)
+
+	err := server.ReloadInterceptors()
+	if it.Nil(err) {
+		it.Equal("v2", first.Value())
+		it.Equal("v2", second.Value())
+
+		status := server.ReloadStatus()
+		it.Equal(ReloadStatusOK, status.Status)
+		it.Equal(filename, status.Filename)
+		it.Equal([]string{"first", "second"}, status.Interceptors)
+	}
+
+	// it should roll back if anyone failed
+	ioutil.WriteFile(filename, []byte("first:\n  value: v3\nsecond:\n  value: invalid\n"), 0644)
+
+	err = server.ReloadInterceptors()
+	if it.NotNil(err) {
+		it.Equal("v2", first.Value())
+		it.Equal("v2", second.Value())
+
+		status := server.ReloadStatus()
+		it.Equal(ReloadStatusFail, status.Status)
+		it.Contains(status.Error, "second.Reload(): invalid value")
+		it.Empty(status.Interceptors)
+
+		var data struct {
+			Value string `yaml:"value"`
+		}
+		it.Nil(server.Config().Interceptors().Unmarshal("first", &data))
+		it.Equal("v2", data.Value)
+	}
+
+	// it should keep the old config if file is invalid
+	ioutil.WriteFile(filename, []byte("first: ["), 0644)
+
+	err = server.ReloadInterceptors()
+	if it.NotNil(err) {
+		var data struct {
+			Value string `yaml:"value"`
+		}
+		it.Nil(server.Config().Interceptors().Unmarshal("first", &data))
+		it.Equal("v2", data.Value)
+	}
+}
+
+func Test_ServerReloadInterceptorsWithoutReloadable(t *testing.T) {
+	it := assert.New(t)
+
+	server, filename, cleanup := fakeReloadServer(t)
+	defer cleanup()
+
+	// it should ignore config which does not implement ReloadableConfiger
+	server.config = struct{ Configer }{server.config}
+
+	first := &stubReloadInterceptor{stubInterceptor: &stubInterceptor{
+		name: "first",
+		apply: func(w http.ResponseWriter, r *http.Request) bool {
+			return true
+		},
+	}}
+	it.Nil(server.WithRequestReceived(first))
+
+	ioutil.WriteFile(filename, []byte("first:\n  value: v2\n"), 0644)
+
+	it.Nil(server.ReloadInterceptors())
+	it.Empty(first.Value())
+	it.Nil(server.ReloadStatus())
+	it.Empty(server.interceptorsFilename())
+}
+
+func Test_ServerWatchInterceptors(t *testing.T) {
+	it := assert.New(t)
+
+	server, filename, cleanup := fakeReloadServer(t)
+	defer cleanup()
+
+	first := &stubReloadInterceptor{stubInterceptor: &stubInterceptor{
+		name: "first",
+		apply: func(w http.ResponseWriter, r *http.Request) bool {
+			return true
+		},
+	}}
+	it.Nil(server.WithRequestReceived(first))
+
+	server.watchInterceptors(10 * time.Millisecond)
+	defer func() {
+		for _, fn := range server.localShutdown {
+			fn()
+		}
+	}()
+
+	ioutil.WriteFile(filename, []byte("first:\n  value: v2\n"), 0644)
+
+	modtime := time.Now().Add(time.Second)
+	os.Chtimes(filename, modtime, modtime)
+
+	for i := 0; i < 100 && first.Value() != "v2"; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	it.Equal("v2", first.Value())
+}
+
+func Test_ReloadHandle(t *testing.T) {
+	it := assert.New(t)
+
+	server, filename, cleanup := fakeReloadServer(t)
+	defer cleanup()
+
+	handler, err := NewReloadHandle(server, nil)
+	if !it.Nil(err) {
+		return
+	}
+
+	r := httptest.NewRequest(http.MethodGet, GogoAdmin+"reload", nil)
+	r.RemoteAddr = "127.0.0.1:8080"
+
+	w := httptest.NewRecorder()
+	handler.Handle(w, r, nil)
+	if it.Equal(http.StatusOK, w.Code) {
+		var status ReloadStatus
+		if it.Nil(json.NewDecoder(w.Body).Decode(&status)) {
+			it.Equal(ReloadStatusNever, status.Status)
+			it.Equal(filename, status.Filename)
+		}
+	}
+
+	server.ReloadInterceptors()
+
+	w = httptest.NewRecorder()
+	handler.Handle(w, r, nil)
+	if it.Equal(http.StatusOK, w.Code) {
+		var status ReloadStatus
+		if it.Nil(json.NewDecoder(w.Body).Decode(&status)) {
+			it.Equal(ReloadStatusOK, status.Status)
+		}
+	}
+
+	// it should deny remote clients by default
+	r.RemoteAddr = "10.0.0.1:8080"
+
+	w = httptest.NewRecorder()
+	handler.Handle(w, r, nil)
+	it.Equal(http.StatusForbidden, w.Code)
+}
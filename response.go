@@ -93,6 +93,11 @@ func (r *Response) FlushHeader() {
 	r.ResponseWriter.WriteHeader(r.status)
 }
 
+// Unwrap returns the underlying http.ResponseWriter for http.ResponseController
+func (r *Response) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
 // Hijack resets the current *Response with new http.ResponseWriter
 func (r *Response) Hijack(w http.ResponseWriter) {
 	r.ResponseWriter = w
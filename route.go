@@ -0,0 +1,324 @@
+package gogo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolab/gogo/pkgs/metadata"
+)
+
+// anyMethods defines request methods registered by AppGroup.Any
+var anyMethods = []string{
+	http.MethodGet,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodHead,
+	http.MethodOptions,
+}
+
+// A RouteOption defines option of a route when registering
+type RouteOption func(opts *routeOptions)
+
+// routeOptions defines options resolved of a route
+type routeOptions struct {
+	timeout *time.Duration
+	name    string
+	meta    metadata.Metadata
+}
+
+// RouteTimeout overwrites handler timeout of server and group for the route,
+// zero disables the timeout.
+func RouteTimeout(timeout time.Duration) RouteOption {
+	return func(opts *routeOptions) {
+		opts.timeout = &timeout
+	}
+}
+
+// RouteName names the route for reverse URL generation by URLFor. Routes of the
+// same name must have the same path within a host, e.g. registered by Any.
+//
+// NOTE: It's used as name prefix of routes generated by Resource, e.g. users.show.
+func RouteName(name string) RouteOption {
+	return func(opts *routeOptions) {
+		opts.name = name
+	}
+}
+
+// RouteMeta attaches metadata of key and value to the route, e.g. auth scopes and
+// owner team. It's readable by ctx.Route() for handlers, RouteFromRequest for
+// hooks and metadata.FromRequest for interceptors.
+func RouteMeta(key string, value interface{}) RouteOption {
+	return func(opts *routeOptions) {
+		if opts.meta == nil {
+			opts.meta = make(metadata.Metadata)
+		}
+
+		opts.meta[key] = value
+	}
+}
+
+// newRouteOptions returns options inherited from group and applied with opts
+func newRouteOptions(group *AppGroup, opts []RouteOption) *routeOptions {
+	options := &routeOptions{
+		timeout: group.timeout,
+	}
+
+	for _, opt := range group.options {
+		opt(options)
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return options
+}
+
+// A Route defines metadata of a route registered
+type Route struct {
+	Name        string `json:"name,omitempty"`
+	Host        string `json:"host,omitempty"` // empty for the default host
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	Package     string `json:"package"`
+	Controller  string `json:"controller"`
+	Action      string `json:"action"`
+	Middlewares int    `json:"middlewares"` // number of group filters, excluding handler of the route
+
+	Meta metadata.Metadata `json:"meta,omitempty"`
+}
+
+// newRoute returns *Route with metadata of ContextHandle
+func newRoute(method, uri string, ch *ContextHandle) *Route {
+	middlewares := len(ch.filters)
+	if ch.handler == nil {
+		middlewares--
+	}
+
+	return &Route{
+		Name:        ch.name,
+		Method:      method,
+		Path:        uri,
+		Package:     ch.pkg,
+		Controller:  ch.ctrl,
+		Action:      ch.action,
+		Middlewares: middlewares,
+		Meta:        ch.meta,
+	}
+}
+
+// RouteFromContext returns *Route stored in ctx, it returns nil if the request
+// is not routed, e.g. route not found.
+//
+// NOTE: The returned *Route is shared by all requests of the route, DO NOT modify it.
+func RouteFromContext(ctx context.Context) *Route {
+	route, _ := ctx.Value(ctxRouteKey).(*Route)
+
+	return route
+}
+
+// RouteFromRequest returns *Route which request routed to, it's useful for hooks,
+// e.g. RequestRouted and ResponseAlways.
+func RouteFromRequest(r *http.Request) *Route {
+	return RouteFromContext(r.Context())
+}
+
+// joinActionPath returns path of custom action relative to the base
+func joinActionPath(base, action string) string {
+	action = strings.Trim(action, "/")
+	if action == "" {
+		return base
+	}
+
+	return base + "/" + action
+}
+
+// actionName returns name of custom action, it's resolved by path without params
+// if not specified, e.g. items.latest for items/:item/latest, or method in lower
+// case for path without static segments.
+func actionName(action ControllerAction) string {
+	if action.Name != "" {
+		return action.Name
+	}
+
+	uri, _ := parseConstraints(strings.Trim(action.Path, "/"))
+
+	var names []string
+	for _, segment := range strings.Split(uri, "/") {
+		if segment == "" || segment[0] == ':' || segment[0] == '*' {
+			continue
+		}
+
+		names = append(names, segment)
+	}
+
+	if len(names) == 0 {
+		return strings.ToLower(action.Method)
+	}
+
+	return strings.Join(names, ".")
+}
+
+// controllerNames returns package and type names of controller, it's formatted
+// the same as ContextHandle resolved, e.g. gogo and *_Controller.
+func controllerNames(controller interface{}) (pkg, ctrl string) {
+	rtype := reflect.TypeOf(controller)
+	if rtype.Kind() == reflect.Ptr {
+		rtype = rtype.Elem()
+		ctrl = "*"
+	}
+
+	return path.Base(rtype.PkgPath()), ctrl + rtype.Name()
+}
+
+// Routes returns all routes registered by groups of server, sorted by host, path
+// and method. Routes of the default host come first.
+//
+// NOTE: Internal routes with prefix /-/ are not included.
+func (s *AppServer) Routes() []Route {
+	s.localRouteMux.RLock()
+	routes := make([]Route, len(s.localRoutes))
+	for i, route := range s.localRoutes {
+		routes[i] = *route
+	}
+	s.localRouteMux.RUnlock()
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Host != routes[j].Host {
+			return routes[i].Host < routes[j].Host
+		}
+
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+
+		return routes[i].Method < routes[j].Method
+	})
+
+	return routes
+}
+
+func (s *AppServer) addRoute(route *Route) {
+	if route.Name != "" {
+		s.localNames.add(route.Host, route.Name, route.Path)
+	}
+
+	s.localRouteMux.Lock()
+	s.localRoutes = append(s.localRoutes, route)
+	s.localRouteMux.Unlock()
+}
+
+// URLFor returns path of route named with params in order, e.g. /users/1 for
+// URLFor("users.show", 1) with route /users/:user. Params are formatted by
+// fmt.Sprint and URL-escaped, the catch-all param keeps its slashes.
+//
+// NOTE: It resolves routes of the default host only, see HostURLFor for routes
+// of hosts. It returns ErrRouteNotNamed for unknown name, ErrRouteParamsMissing
+// and ErrRouteParamsExtra if number of params mismatched.
+func (s *AppServer) URLFor(name string, params ...interface{}) (string, error) {
+	return s.localNames.urlFor("", name, params...)
+}
+
+// HostURLFor returns path of route named within host of pattern given by Host,
+// it falls back to routes of the default host if not found. See URLFor for details.
+func (s *AppServer) HostURLFor(pattern, name string, params ...interface{}) (string, error) {
+	return s.localNames.urlFor(normalizeHostPattern(pattern), name, params...)
+}
+
+// routeNames defines paths of routes named by host, the empty host is the default.
+type routeNames struct {
+	mux   sync.RWMutex
+	paths map[string]map[string]string
+}
+
+// add registers path with name of host, it panics if name has been registered
+// with another path of the same host.
+func (rn *routeNames) add(host, name, uri string) {
+	rn.mux.Lock()
+	defer rn.mux.Unlock()
+
+	if rn.paths == nil {
+		rn.paths = make(map[string]map[string]string)
+	}
+
+	paths, ok := rn.paths[host]
+	if !ok {
+		paths = make(map[string]string)
+
+		rn.paths[host] = paths
+	}
+
+	if registered, ok := paths[name]; ok && registered != uri {
+		panic(ErrRouteNameDuplicated)
+	}
+
+	paths[name] = uri
+}
+
+// lookup returns path of name within host, it falls back to the default host.
+func (rn *routeNames) lookup(host, name string) (uri string, ok bool) {
+	rn.mux.RLock()
+	defer rn.mux.RUnlock()
+
+	uri, ok = rn.paths[host][name]
+	if !ok && host != "" {
+		uri, ok = rn.paths[""][name]
+	}
+
+	return
+}
+
+func (rn *routeNames) urlFor(host, name string, params ...interface{}) (string, error) {
+	if rn == nil {
+		return "", ErrRouteNotNamed
+	}
+
+	uri, ok := rn.lookup(host, name)
+	if !ok {
+		return "", ErrRouteNotNamed
+	}
+
+	segments := strings.Split(uri, "/")
+
+	cursor := 0
+	for i, segment := range segments {
+		if len(segment) == 0 || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+
+		if cursor >= len(params) {
+			return "", ErrRouteParamsMissing
+		}
+
+		value := fmt.Sprint(params[cursor])
+		cursor++
+
+		if segment[0] == ':' {
+			segments[i] = url.PathEscape(value)
+			continue
+		}
+
+		// catch-all param of the last segment
+		values := strings.Split(strings.TrimPrefix(value, "/"), "/")
+		for j, v := range values {
+			values[j] = url.PathEscape(v)
+		}
+
+		segments[i] = strings.Join(values, "/")
+	}
+
+	if cursor != len(params) {
+		return "", ErrRouteParamsExtra
+	}
+
+	return strings.Join(segments, "/"), nil
+}
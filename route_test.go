@@ -0,0 +1,234 @@
+package gogo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dolab/gogo/pkgs/metadata"
+	"github.com/golib/assert"
+)
+
+func Test_ServerRoutes(t *testing.T) {
+	it := assert.New(t)
+	server := fakeServer()
+
+	group := server.NewGroup("/v1", func(ctx *Context) {
+		ctx.Next()
+	})
+	group.GET("/action", fakePackageAction)
+	group.Resource("/group", &testGroupController{})
+	group.HandlerFunc(http.MethodPost, "/handler", fakePackageHandler)
+	server.Static("/assets", "testdata")
+
+	routes := server.Routes()
+	if it.Len(routes, 6) {
+		it.Equal(Route{
+			Method:      http.MethodGet,
+			Path:        "/assets/*filepath",
+			Package:     "gogo",
+			Controller:  "AppGroup",
+			Action:      "Static",
+			Middlewares: 0,
+		}, routes[0])
+
+		it.Equal(Route{
+			Method:      http.MethodHead,
+			Path:        "/assets/*filepath",
+			Package:     "gogo",
+			Controller:  "AppGroup",
+			Action:      "Static",
+			Middlewares: 0,
+		}, routes[1])
+
+		it.Equal(Route{
+			Method:      http.MethodGet,
+			Path:        "/v1/action",
+			Package:     "gogo",
+			Controller:  "gogo",
+			Action:      "fakePackageAction",
+			Middlewares: 1,
+		}, routes[2])
+
+		it.Equal(http.MethodGet, routes[3].Method)
+		it.Equal("/v1/group", routes[3].Path)
+		it.Equal("*testGroupController", routes[3].Controller)
+		it.Equal("Index", routes[3].Action)
+
+		it.Equal("/v1/group/:group", routes[4].Path)
+		it.Equal("Show", routes[4].Action)
+
+		it.Equal(http.MethodPost, routes[5].Method)
+		it.Equal("fakePackageHandler", routes[5].Action)
+		it.Equal(1, routes[5].Middlewares)
+	}
+}
+
+func Test_ServerRoutesWithHandle(t *testing.T) {
+	it := assert.New(t)
+	logger := NewAppLogger("nil", "")
+	config, _ := fakeConfig("application.routes.yml")
+
+	server := NewAppServer(config, logger)
+	server.GET("/routes", fakePackageAction)
+	server.prepare()
+
+	r, _ := http.NewRequest(http.MethodGet, GogoRoutes, nil)
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusOK, w.Code)
+	it.Equal("application/json", w.Header().Get("Content-Type"))
+
+	var routes []Route
+	if it.Nil(json.NewDecoder(w.Body).Decode(&routes)) && it.Len(routes, 1) {
+		it.Equal("/routes", routes[0].Path)
+		it.Equal("fakePackageAction", routes[0].Action)
+	}
+
+	// it should be disabled by default
+	server = fakeServer()
+	server.prepare()
+
+	w = httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusNotFound, w.Code)
+}
+
+func Test_ServerURLFor(t *testing.T) {
+	it := assert.New(t)
+	server := fakeServer()
+
+	group := server.NewGroup("/v1")
+	group.GET("/action", fakePackageAction, RouteName("action"))
+	group.Any("/any/:name", fakePackageAction, RouteName("any"))
+	articles := group.Resource("/articles", &testGroupController{})
+	articles.Resource("comments", &testGroupController{})
+	group.Resource("/users", &testGroupController{}, RouteName("accounts"))
+	server.GET("/files/*filepath", fakePackageAction, RouteName("files"))
+
+	testCases := []struct {
+		name     string
+		params   []interface{}
+		expected string
+	}{
+		{"action", nil, "/v1/action"},
+		{"any", []interface{}{"a b/c"}, "/v1/any/a%20b%2Fc"},
+		{"articles.index", nil, "/v1/articles"},
+		{"articles.show", []interface{}{1}, "/v1/articles/1"},
+		{"articles.comments.show", []interface{}{1, "x"}, "/v1/articles/1/comments/x"},
+		{"accounts.show", []interface{}{"me"}, "/v1/users/me"},
+		{"files", []interface{}{"/css/a b.css"}, "/files/css/a%20b.css"},
+	}
+	for _, testCase := range testCases {
+		uri, err := server.URLFor(testCase.name, testCase.params...)
+		if it.Nil(err, testCase.name) {
+			it.Equal(testCase.expected, uri, testCase.name)
+		}
+	}
+
+	routes := server.Routes()
+	if it.NotEmpty(routes) {
+		it.Equal("files", routes[0].Name)
+	}
+
+	// errors
+	_, err := server.URLFor("unknown")
+	it.Equal(ErrRouteNotNamed, err)
+
+	_, err = server.URLFor("articles.show")
+	it.Equal(ErrRouteParamsMissing, err)
+
+	_, err = server.URLFor("articles.index", 1)
+	it.Equal(ErrRouteParamsExtra, err)
+
+	// it should panic with another path of the same name
+	it.Panics(func() {
+		server.GET("/another", fakePackageAction, RouteName("action"))
+	})
+}
+
+func Test_ContextURLFor(t *testing.T) {
+	it := assert.New(t)
+	server := fakeServer()
+
+	server.GET("/users/:user", fakePackageAction, RouteName("users.show"))
+	server.POST("/users", func(ctx *Context) {
+		location, err := ctx.URLFor("users.show", "new user")
+		if err != nil {
+			ctx.Text(err.Error())
+			return
+		}
+
+		ctx.Redirect(location)
+	})
+
+	r, _ := http.NewRequest(http.MethodPost, "/users", nil)
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal("/users/new%20user", w.Header().Get("Location"))
+}
+
+func Test_ServerRouteMeta(t *testing.T) {
+	it := assert.New(t)
+	server := fakeServer()
+
+	var (
+		routed *Route
+		always metadata.Metadata
+		noMeta bool
+	)
+	server.RequestRouted.PushBack(func(w http.ResponseWriter, r *http.Request) bool {
+		routed = RouteFromRequest(r)
+		return true
+	})
+	server.ResponseAlways.PushBack(func(w http.ResponseWriter, r *http.Request) bool {
+		always = metadata.FromRequest(r)
+		return true
+	})
+
+	server.GET("/orders/:order", func(ctx *Context) {
+		route := ctx.Route()
+		if it.NotNil(route) {
+			ctx.Text(route.Meta.String("owner"))
+		}
+	}, RouteName("orders.show"), RouteMeta("owner", "payment"), RouteMeta("scopes", []string{"orders:read"}))
+	server.GET("/healthy", func(ctx *Context) {
+		noMeta = ctx.Route().Meta == nil
+	})
+
+	r, _ := http.NewRequest(http.MethodGet, "/orders/1", nil)
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusOK, w.Code)
+	it.Equal("payment", w.Body.String())
+
+	if it.NotNil(routed) {
+		it.Equal("orders.show", routed.Name)
+		it.Equal("/orders/:order", routed.Path)
+		it.Equal([]string{"orders:read"}, routed.Meta.Strings("scopes"))
+	}
+	it.Equal("payment", always.String("owner"))
+
+	// it should work without metadata
+	r, _ = http.NewRequest(http.MethodGet, "/healthy", nil)
+	w = httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.True(noMeta)
+	it.Nil(always)
+	if it.NotNil(routed) {
+		it.Equal("/healthy", routed.Path)
+	}
+
+	// it should be listed by Routes
+	routes := server.Routes()
+	if it.Len(routes, 2) {
+		it.Nil(routes[0].Meta)
+		it.Equal("payment", routes[1].Meta.String("owner"))
+	}
+}
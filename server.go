@@ -3,25 +3,18 @@ package gogo
 import (
 	"context"
 	"fmt"
-	"net/http"
 	"net/url"
 	"os"
 	"os/signal"
-	"regexp"
-	"strconv"
-	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/dolab/gogo/internal/listeners"
 	"github.com/dolab/gogo/pkgs/hooks"
 	"github.com/dolab/gogo/pkgs/interceptors"
 	"github.com/dolab/gogo/pkgs/interceptors/debugger"
-	"golang.org/x/net/http2"
-)
-
-var (
-	network2addr = regexp.MustCompile(`(?i)^(http|https|tcp|tcp4|tcp6|unix|unixpacket):/{1,3}?(.+)?`)
+	"github.com/dolab/gogo/pkgs/interceptors/mtls"
 )
 
 // AppServer defines a server component of gogo
@@ -34,11 +27,27 @@ type AppServer struct {
 	requestID    string   // request id header name
 	filterFields []string // filter out params when logging
 
-	localMux    sync.RWMutex
-	localSig    chan os.Signal
-	localAddr   string
-	localIfaces []interface{}
-	localServ   *http.Server
+	localMux        sync.RWMutex
+	localSig        chan os.Signal
+	localPrepare    sync.Once // prepare once for retrying Start after failure
+	localIfaces     []interface{}
+	localListeners  []*appListener
+	localShutdown   []func()
+	localDraining   int32
+	localUpgraded   int32 // it's 1 after child process upgraded ready
+	localDone       chan struct{}
+	localErr        error
+	localComponents []Component
+	localStarted    int // number of components started
+	localChecks     []*healthCheck
+	localRouteMux   sync.RWMutex // guards localRoutes, groups register routes with localMux held
+	localRoutes     []*Route
+	localHosts      virtualHosts
+	localNames      routeNames
+	localReloader   interceptorReloader
+	localThrottle   *runtimeLimit
+	localDemotion   *runtimeLimit
+	localDeadline   handlerDeadline
 }
 
 // NewAppServer returns *AppServer inited with args
@@ -49,6 +58,7 @@ func NewAppServer(config Configer, logger Logger) *AppServer {
 		requestID: DefaultRequestIDKey,
 		localIfaces: []interface{}{
 			debugger.NewRegistry(),
+			mtls.NewRegistry(),
 		},
 	}
 
@@ -71,12 +81,51 @@ func (s *AppServer) Config() Configer {
 	return s.config
 }
 
-// Address returns app listen address
+// Address returns the first bound address of app listeners,
+// see Addresses for all bound addresses.
 func (s *AppServer) Address() string {
 	s.localMux.RLock()
 	defer s.localMux.RUnlock()
 
-	return s.localAddr
+	if len(s.localListeners) == 0 {
+		return ""
+	}
+
+	return s.localListeners[0].Addr()
+}
+
+// Addresses returns all bound addresses of app listeners in order of config.
+func (s *AppServer) Addresses() []string {
+	s.localMux.RLock()
+	defer s.localMux.RUnlock()
+
+	addrs := make([]string, len(s.localListeners))
+	for i, listener := range s.localListeners {
+		addrs[i] = listener.Addr()
+	}
+
+	return addrs
+}
+
+// ListenerStats returns counters of connections for all listeners in order of config.
+func (s *AppServer) ListenerStats() []ListenerStats {
+	s.localMux.RLock()
+	defer s.localMux.RUnlock()
+
+	stats := make([]ListenerStats, len(s.localListeners))
+	for i, listener := range s.localListeners {
+		lstats := listener.listener.Stats()
+
+		stats[i] = ListenerStats{
+			Network:  listener.network,
+			Address:  listener.Addr(),
+			Accepted: lstats.Accepted,
+			Rejected: lstats.Rejected,
+			Active:   lstats.Active,
+		}
+	}
+
+	return stats
 }
 
 // WithInterceptors tries to register all interceptors defined by iface
@@ -116,6 +165,8 @@ func (s *AppServer) WithRequestReceived(m interceptors.Interface) error {
 		return fmt.Errorf("Middleware conflict, %q has registered for request received phase", name)
 	}
 
+	s.withLogger(m)
+
 	applier, err := m.Register(s.config.Interceptors())
 	if err != nil {
 		return err
@@ -127,6 +178,9 @@ func (s *AppServer) WithRequestReceived(m interceptors.Interface) error {
 		Priority: m.Priority(),
 	})
 
+	s.withShutdowner(m)
+	s.localReloader.add(m)
+
 	return nil
 }
 
@@ -140,6 +194,8 @@ func (s *AppServer) WithRequestRouted(m interceptors.Interface) error {
 		return fmt.Errorf("Middleware conflict, %q has registered for request routed phase", name)
 	}
 
+	s.withLogger(m)
+
 	applier, err := m.Register(s.config.Interceptors())
 	if err != nil {
 		return err
@@ -151,6 +207,9 @@ func (s *AppServer) WithRequestRouted(m interceptors.Interface) error {
 		Priority: m.Priority(),
 	})
 
+	s.withShutdowner(m)
+	s.localReloader.add(m)
+
 	return nil
 }
 
@@ -164,6 +223,8 @@ func (s *AppServer) WithResponseReady(m interceptors.Interface) error {
 		return fmt.Errorf("Middleware conflict, %q has registered for response ready phase", name)
 	}
 
+	s.withLogger(m)
+
 	applier, err := m.Register(s.config.Interceptors())
 	if err != nil {
 		return err
@@ -175,6 +236,9 @@ func (s *AppServer) WithResponseReady(m interceptors.Interface) error {
 		Priority: m.Priority(),
 	})
 
+	s.withShutdowner(m)
+	s.localReloader.add(m)
+
 	return nil
 }
 
@@ -188,6 +252,8 @@ func (s *AppServer) WithResponseAlways(m interceptors.Interface) error {
 		return fmt.Errorf("Middleware conflict, %q has registered for response always phase", name)
 	}
 
+	s.withLogger(m)
+
 	applier, err := m.Register(s.config.Interceptors())
 	if err != nil {
 		return err
@@ -199,9 +265,19 @@ func (s *AppServer) WithResponseAlways(m interceptors.Interface) error {
 		Priority: m.Priority(),
 	})
 
+	s.withShutdowner(m)
+	s.localReloader.add(m)
+
 	return nil
 }
 
+// withLogger injects logger of server if interceptor implements interceptors.Logable
+func (s *AppServer) withLogger(m interceptors.Interface) {
+	if logable, ok := m.(interceptors.Logable); ok {
+		logable.SetLogger(s.loggerNew("GOGO"))
+	}
+}
+
 // NewService register all resources of service with interceptorss
 func (s *AppServer) NewService(svc Servicer) *AppServer {
 	// first, try to register all interceptorss defined by service
@@ -240,19 +316,246 @@ func (s *AppServer) NewResources(svc Servicer) *AppServer {
 	return s
 }
 
-// Run starts the http server with AppGroup as http.Handler.
+// RegisterOnShutdown registers a function to call after all connections
+// have been drained by graceful shutdown. Functions are called in order of
+// registration.
+func (s *AppServer) RegisterOnShutdown(fn func()) {
+	s.localMux.Lock()
+	s.localShutdown = append(s.localShutdown, fn)
+	s.localMux.Unlock()
+}
+
+// Run starts the http server with AppGroup as http.Handler, and blocks until
+// all listeners closed. It's a wrapper of Start and Wait which exits on error.
 //
 // NOTE: Run apply throughput and concurrency to http.Server.
 func (s *AppServer) Run() {
-	var (
-		config         = s.config.Section()
-		network        = "tcp"
-		addr           = config.Server.Addr
-		port           = config.Server.Port
-		rtimeout       = DefaultRequestTimeout
-		wtimeout       = DefaultResponseTimeout
-		maxHeaderBytes = 0
-	)
+	err := s.Start(context.Background())
+	if err == nil {
+		err = s.Wait()
+	}
+
+	if err != nil {
+		s.loggerNew("GOGO").Fatal(err)
+	}
+}
+
+// Start registers internal hooks, starts all components, announces on all
+// listeners and serves them in background. It returns once all listeners are
+// ready for accepting connections, and the ctx is used for starting only.
+// Use Wait for blocking until server closed, and Stop for shutting down gracefully.
+//
+// NOTE: Start returns *ListenError for binding failure and *TLSError for tls
+// misconfiguration, listeners announced and components started before are
+// closed in that case, and it's safe to retry Start later.
+func (s *AppServer) Start(ctx context.Context) error {
+	s.localMux.Lock()
+	if s.localDone != nil {
+		s.localMux.Unlock()
+
+		return ErrServerStarted
+	}
+
+	done := make(chan struct{})
+	s.localDone = done
+	s.localMux.Unlock()
+
+	// NOTE: it clears started state for retrying
+	fail := func(err error) error {
+		s.localMux.Lock()
+		s.localErr = err
+		s.localDone = nil
+		s.localMux.Unlock()
+
+		close(done)
+
+		return err
+	}
+
+	s.localPrepare.Do(s.prepare)
+
+	err := s.startComponents(ctx)
+	if err != nil {
+		return fail(err)
+	}
+
+	locals, err := s.listen(ctx)
+	if err != nil {
+		s.stopComponents()
+
+		return fail(err)
+	}
+
+	err = s.readyComponents(ctx)
+	if err != nil {
+		// NOTE: hooks of shutdown are invoked asynchronously, listeners must be
+		// closed before returning for retrying.
+		for _, local := range locals {
+			local.listener.Shutdown()
+			local.server.Shutdown(context.Background())
+
+			s.RequestReceived.PopNamed(local.listener.RequestReceivedHook().Name)
+		}
+
+		s.stopComponents()
+
+		return fail(err)
+	}
+
+	// register locals
+	s.localMux.Lock()
+	s.localListeners = locals
+	s.localMux.Unlock()
+
+	// watch interceptors
+	interval := DefaultInterceptorsInterval
+	if config := s.config.Section().Server; config.InterceptorsInterval != 0 {
+		interval = config.InterceptorsInterval
+	}
+	if interval > 0 {
+		s.watchInterceptors(time.Duration(interval) * time.Second)
+	}
+
+	log := s.loggerNew("GOGO")
+
+	// notify parent process for upgrading
+	if err := listeners.NotifyReady(); err != nil {
+		log.Errorf("listeners.NotifyReady(): %v", err)
+	}
+
+	// notify systemd with main pid, it's changed after upgrading
+	if err := listeners.SdNotify(fmt.Sprintf("%s\nMAINPID=%d", listeners.SdReady, os.Getpid())); err != nil {
+		log.Errorf("listeners.SdNotify(%s): %v", listeners.SdReady, err)
+	}
+
+	if timeout := listeners.SdWatchdogTimeout(); timeout > 0 {
+		go s.watchdog(timeout/2, done)
+	}
+
+	errc := make(chan error, len(locals))
+	for _, local := range locals {
+		go func(local *appListener) {
+			errc <- local.serve()
+		}(local)
+	}
+
+	go func() {
+		// closes all listeners once anyone failed
+		var err error
+		for range locals {
+			if lerr := <-errc; lerr != nil && err == nil {
+				err = lerr
+
+				for _, local := range locals {
+					local.server.Close()
+				}
+			}
+		}
+
+		if err == nil {
+			log.Info("Server shutdown")
+		}
+
+		s.localMux.Lock()
+		s.localErr = err
+		s.localMux.Unlock()
+
+		close(done)
+	}()
+
+	return nil
+}
+
+// Wait blocks until all listeners of server closed. It returns nil if server
+// shutdown gracefully, otherwise returns the first error of serving.
+func (s *AppServer) Wait() error {
+	s.localMux.RLock()
+	done := s.localDone
+	s.localMux.RUnlock()
+
+	if done == nil {
+		return ErrServerNotStarted
+	}
+
+	<-done
+
+	s.localMux.RLock()
+	defer s.localMux.RUnlock()
+
+	return s.localErr
+}
+
+// Stop shuts down server gracefully. It marks server as draining, stops accepting
+// new connections and waits for in-flight requests until the ctx is done, then
+// notifies all functions registered by RegisterOnShutdown in order, and stops
+// all components in reverse order within component timeout at last.
+//
+// NOTE: Stop returns ErrShutdownTimeout if connections are closed forcibly.
+func (s *AppServer) Stop(ctx context.Context) (err error) {
+	s.localMux.RLock()
+	done := s.localDone
+	locals := s.localListeners
+	fns := s.localShutdown
+	s.localMux.RUnlock()
+
+	if done == nil {
+		return ErrServerNotStarted
+	}
+
+	// it's shutting down
+	if !atomic.CompareAndSwapInt32(&s.localDraining, 0, 1) {
+		<-done
+		return
+	}
+
+	// NOTE: systemd tracks the child process after upgraded
+	if atomic.LoadInt32(&s.localUpgraded) == 0 {
+		if serr := listeners.SdNotify(listeners.SdStopping); serr != nil {
+			s.loggerNew("GOGO").Errorf("listeners.SdNotify(%s): %v", listeners.SdStopping, serr)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, local := range locals {
+		wg.Add(1)
+
+		go func(local *appListener) {
+			defer wg.Done()
+
+			if local.shutdown(ctx) != nil {
+				local.server.Close()
+			}
+		}(local)
+	}
+	wg.Wait()
+
+	switch ctx.Err() {
+	case nil:
+		// shutdown gracefully
+
+	case context.DeadlineExceeded:
+		err = ErrShutdownTimeout
+
+	default:
+		err = ctx.Err()
+	}
+
+	for _, fn := range fns {
+		fn()
+	}
+
+	if cerr := s.stopComponents(); err == nil {
+		err = cerr
+	}
+
+	<-done
+
+	return
+}
+
+// prepare registers internal interceptors and hooks of config
+func (s *AppServer) prepare() {
+	config := s.config.Section()
 
 	// register all middlewares of internal
 	for _, iface := range s.localIfaces {
@@ -264,20 +567,74 @@ func (s *AppServer) Run() {
 		s.registerHealthz()
 	}
 
-	// throughput of rate limit
-	if config.Server.Throttle > 0 {
-		s.RequestReceived.PushFrontNamed(
-			hooks.NewServerThrottleHook(config.Server.Throttle),
-		)
+	// register route table
+	if config.Server.Routes {
+		s.registerRoutes()
+	}
+
+	// register debug endpoints, it's disabled if allowlist is invalid
+	if config.Server.Debug.IsEnabled(s.config.RunMode()) {
+		err := s.registerDebug(config.Server.Debug)
+		if err != nil {
+			s.loggerNew("GOGO").Errorf("Register %s: %v", GogoDebug, err)
+		}
+	}
+
+	// register admin endpoints, it's disabled if allowlist is invalid
+	if config.Server.Admin.IsEnabled() {
+		err := s.registerAdmin(config.Server.Admin)
+		if err != nil {
+			s.loggerNew("GOGO").Errorf("Register %s: %v", GogoAdmin, err)
+		}
 	}
 
-	// concurrency of bucket token
-	if config.Server.Demotion > 0 {
-		s.RequestReceived.PushBackNamed(
-			hooks.NewServerDemotionHook(config.Server.Demotion, config.Server.RTimeout),
+	// throughput of rate limit, it's adjustable at runtime by admin endpoints
+	if config.Server.Throttle > 0 || config.Server.Admin.IsEnabled() {
+		throttle := hooks.NewServerThrottle(config.Server.Throttle)
+
+		s.localMux.Lock()
+		s.localThrottle = newRuntimeLimit("throttle", throttle)
+		s.localMux.Unlock()
+
+		s.RequestReceived.PushFrontNamed(throttle.Hook())
+	}
+
+	// concurrency of bucket token, it's adjustable at runtime by admin endpoints
+	if config.Server.Demotion > 0 || config.Server.Admin.IsEnabled() {
+		demotion := hooks.NewServerDemotion(config.Server.Demotion, config.Server.RTimeout)
+
+		s.localMux.Lock()
+		s.localDemotion = newRuntimeLimit("demotion", demotion)
+		s.localMux.Unlock()
+
+		s.RequestReceived.PushBackNamed(demotion.Hook())
+		s.RegisterOnShutdown(demotion.Close)
+	}
+
+	// answer OPTIONS and 405 automatically for all hosts, it's disabled if cors is invalid
+	if config.Server.AutoOptions {
+		var (
+			policy *corsPolicy
+			err    error
 		)
+		if config.Server.CORS != nil {
+			policy, err = newCORSPolicy(config.Server.CORS)
+		}
+
+		if err != nil {
+			s.loggerNew("GOGO").Errorf("Register auto options: %v", err)
+		} else {
+			s.setAutoOptions(policy)
+
+			s.localHosts.each(func(group *AppGroup) {
+				group.setAutoOptions(policy)
+			})
+		}
 	}
 
+	// adjust deadline of handlers
+	s.localDeadline = newHandlerDeadline(config.Server)
+
 	// adjust app server request id if specified
 	if config.Server.RequestID != "" {
 		s.requestID = config.Server.RequestID
@@ -285,132 +642,245 @@ func (s *AppServer) Run() {
 
 	// adjust app logger filter sensitive fields
 	s.filterFields = config.Logger.FilterFields
+}
 
-	// If the port is zero, treat the address as a fully qualified local address.
-	// This address must be prefixed with the network type followed by a colon,
-	// e.g. unix:/tmp/gogo.socket or tcp6:::1 (equivalent to tcp6:0:0:0:0:0:0:0:1)
-	matches := network2addr.FindStringSubmatch(addr)
-	if len(matches) == 3 {
-		switch strings.ToLower(matches[1]) {
-		case "http", "https":
-			// ignore
-		default:
-			network = matches[1]
+// listen announces on all listeners of config
+func (s *AppServer) listen(ctx context.Context) ([]*appListener, error) {
+	config := s.config.Section()
+
+	log := s.loggerNew("GOGO")
+
+	var locals []*appListener
+	for _, lconfig := range config.Server.ListenerConfigs() {
+		local, err := newAppListener(lconfig, s.AppGroup)
+		if err == nil {
+			err = ctx.Err()
+			if err != nil {
+				local.listener.Shutdown()
+			}
 		}
+		if err != nil {
+			for _, local := range locals {
+				local.listener.Shutdown()
 
-		addr = "/" + strings.TrimPrefix(matches[2], "/")
-	}
+				s.RequestReceived.PopNamed(local.listener.RequestReceivedHook().Name)
+			}
 
-	if port != 0 {
-		addr += ":" + strconv.Itoa(port)
-	}
+			return nil, err
+		}
+
+		if local.listener.Inherited() {
+			log.Infof("Inherited on %s://%s", local.network, local.address)
+		} else {
+			log.Infof("Listened on %s://%s", local.network, local.address)
+		}
 
-	if config.Server.RTimeout > 0 {
-		rtimeout = config.Server.RTimeout
+		// NOTE: hook is replaced by name for retried Start
+		s.RequestReceived.SetFrontNamed(local.listener.RequestReceivedHook())
+
+		locals = append(locals, local)
 	}
-	if config.Server.WTimeout > 0 {
-		wtimeout = config.Server.WTimeout
+
+	// watch certificates
+	interval := DefaultSslInterval
+	if config.Server.SslInterval > 0 {
+		interval = config.Server.SslInterval
 	}
-	if config.Server.MaxHeaderBytes > 0 {
-		maxHeaderBytes = config.Server.MaxHeaderBytes
+	for _, local := range locals {
+		local.watch(time.Duration(interval)*time.Second, log)
 	}
 
-	// register server
+	return locals, nil
+}
+
+// RunWithHandler runs the http server with given handler
+// It's useful for embbedding third-party golang applications.
+func (s *AppServer) RunWithHandler(handler Handler) {
+	s.SetHandler(handler)
+
+	s.Run()
+}
+
+// Reload reloads certificates of all listeners and config of all interceptors.
+// It keeps the old if failed.
+func (s *AppServer) Reload() {
+	s.localMux.RLock()
+	locals := s.localListeners
+	s.localMux.RUnlock()
+
 	log := s.loggerNew("GOGO")
+	for _, local := range locals {
+		local.reload(log)
+	}
 
-	listener := listeners.New(config.Server.HTTP2)
-	s.RequestReceived.PushFrontNamed(listener.RequestReceivedHook())
+	s.ReloadInterceptors()
+}
+
+// Serve runs a server with graceful shutdown feature. It blocks until one of
+// shutdown signals received, then stops server with shutdown timeout by Stop.
+//
+// Serve reloads server by calling Reload when received SIGHUP, unless SIGHUP is
+// configured as shutdown or upgrade signal.
+//
+// If upgrade signal is configured, Serve forks a new process of the binary with
+// listeners handed over when received, and drains itself after the new process
+// is ready.
+//
+// NOTE: Serve returns nil if server shutdown gracefully, otherwise returns error.
+func (s *AppServer) Serve() error {
+	config := s.config.Section()
 
-	conn, err := listener.Listen(network, addr)
+	signals, reload, upgrade, err := s.serveSignals()
 	if err != nil {
-		log.Fatalf("listeners.Listen(%s, %s): %v", network, addr, err)
+		return err
 	}
-	log.Infof("Listened on %s://%s", network, addr)
 
-	server := &http.Server{
-		Addr:              addr,
-		Handler:           s.AppGroup,
-		ReadHeaderTimeout: time.Duration(rtimeout) * time.Second,
-		ReadTimeout:       time.Duration(rtimeout) * time.Second,
-		WriteTimeout:      time.Duration(wtimeout) * time.Second,
-		MaxHeaderBytes:    maxHeaderBytes,
+	timeout := DefaultShutdownTimeout
+	if config.Server.ShutdownTimeout > 0 {
+		timeout = config.Server.ShutdownTimeout
 	}
-	server.RegisterOnShutdown(listener.Shutdown)
 
-	// register locals
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, signals...)
+	defer signal.Stop(sigc)
+
 	s.localMux.Lock()
-	s.localAddr = addr
-	s.localServ = server
+	s.localSig = sigc
 	s.localMux.Unlock()
 
-	if config.Server.Ssl {
-		msg := "ServeTLS(%s:%s): %v"
-		if config.Server.HTTP2 {
-			err := http2.ConfigureServer(server, nil)
-			if err != nil {
-				log.Fatalf("http2.ConfigureServer(%T, nil): %v", server, err)
-			}
+	err = s.Start(context.Background())
+	if err != nil {
+		return err
+	}
 
-			msg = "ServeHTTP2(%s:%s): %v"
-		}
+	errc := make(chan error, 1)
+	go func() {
+		errc <- s.Wait()
+	}()
+
+	log := s.loggerNew("GOGO")
+
+	var sig os.Signal
+	for sig == nil {
+		select {
+		case err := <-errc:
+			return err
 
-		if err := server.ServeTLS(conn, config.Server.SslCert, config.Server.SslKey); err != nil {
-			if strings.Contains(err.Error(), "http: Server closed") {
-				log.Info("Server shutdown")
-			} else {
-				log.Fatalf(msg, network, addr, err)
+		case sig = <-sigc:
+			if reload != nil && sig == reload {
+				s.Reload()
+
+				sig = nil
+				break
 			}
-		}
-	} else {
-		if err := server.Serve(conn); err != nil {
-			if strings.Contains(err.Error(), "http: Server closed") {
-				log.Info("Server shutdown")
-			} else {
-				log.Fatalf("Serve(%s:%s): %v", network, addr, err)
+
+			if upgrade == nil || sig != upgrade {
+				break
+			}
+
+			if err := s.Upgrade(); err != nil {
+				log.Errorf("Upgrade(): %v", err)
+
+				sig = nil
 			}
 		}
 	}
-}
 
-// RunWithHandler runs the http server with given handler
-// It's useful for embbedding third-party golang applications.
-func (s *AppServer) RunWithHandler(handler Handler) {
-	s.SetHandler(handler)
+	log.Infof("Shutting down server by %v ....", sig)
 
-	s.Run()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	err = s.Stop(ctx)
+
+	// wait for serving returns
+	if rerr := <-errc; err == nil {
+		err = rerr
+	}
+
+	return err
 }
 
-// Serve runs a server with graceful shutdown feature
-func (s *AppServer) Serve() {
-	s.localSig = make(chan os.Signal, 1)
-	signal.Notify(s.localSig, os.Interrupt)
+// serveSignals returns signals notified by Serve, including signals of shutdown,
+// reload and upgrade. The reload signal is disabled if it's configured for
+// shutdown or upgrade, which takes precedence.
+func (s *AppServer) serveSignals() (signals []os.Signal, reload, upgrade os.Signal, err error) {
+	config := s.config.Section()
 
-	go s.Run()
+	names := config.Server.ShutdownSignals
+	if len(names) == 0 {
+		names = DefaultShutdownSignals
+	}
 
-	<-s.localSig
-	close(s.localSig)
+	signals, err = ParseSignals(names)
+	if err != nil {
+		return
+	}
 
-	log := s.loggerNew("GOGO")
-	log.Info("Shutting down server ....")
+	if config.Server.UpgradeSignal != "" {
+		upgrade, err = ParseSignal(config.Server.UpgradeSignal)
+		if err != nil {
+			return
+		}
 
-	// NOTE: 500*time.Millinsecond is copied from net/http
-	ctx, _ := context.WithTimeout(context.Background(), 500*time.Millisecond)
-	s.localServ.Shutdown(ctx)
+		signals = append(signals, upgrade)
+	}
 
-	select {
-	case <-ctx.Done():
-		os.Exit(0)
+	reload, err = ParseSignal(DefaultReloadSignal)
+	if err != nil {
+		return
+	}
+
+	for _, sig := range signals {
+		if sig == reload {
+			reload = nil
+			return
+		}
 	}
+
+	signals = append(signals, reload)
+	return
 }
 
 // Shutdown shuts down AppServer gracefully by emitting os.Interrupt
 func (s *AppServer) Shutdown() {
-	if s.localSig == nil {
+	s.localMux.RLock()
+	sigc := s.localSig
+	s.localMux.RUnlock()
+
+	if sigc == nil {
 		return
 	}
 
 	// use interrupt sig
-	s.localSig <- os.Interrupt
+	select {
+	case sigc <- os.Interrupt:
+	default:
+		// shutting down
+	}
+}
+
+// watchdog pings systemd within interval until done closed
+func (s *AppServer) watchdog(interval time.Duration, done chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := listeners.SdNotify(listeners.SdWatchdog); err != nil {
+				s.loggerNew("GOGO").Errorf("listeners.SdNotify(%s): %v", listeners.SdWatchdog, err)
+			}
+
+		case <-done:
+			return
+		}
+	}
+}
+
+// isDraining returns true if server is shutting down gracefully
+func (s *AppServer) isDraining() bool {
+	return atomic.LoadInt32(&s.localDraining) == 1
 }
 
 func (s *AppServer) loggerNew(tag string) Logger {
@@ -2,6 +2,7 @@ package gogo
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -13,8 +14,10 @@ import (
 	"time"
 
 	"github.com/dolab/gogo/pkgs/hooks"
+	"github.com/dolab/gogo/pkgs/proxyproto"
 	"github.com/dolab/httptesting"
 	"github.com/golib/assert"
+	"golang.org/x/net/http2"
 )
 
 func Test_ServerWithHealthz(t *testing.T) {
@@ -32,7 +35,12 @@ func Test_ServerWithHealthz(t *testing.T) {
 	request := client.New(t)
 	request.Get(GogoHealthz, nil)
 	request.AssertOK()
-	request.AssertEmpty()
+	request.AssertContainsJSON("status", "ok")
+
+	request = client.New(t)
+	request.Get(GogoReadyz, nil)
+	request.AssertOK()
+	request.AssertContainsJSON("status", "ok")
 }
 
 func Test_ServerWithThroughput(t *testing.T) {
@@ -238,6 +246,434 @@ func Test_ServerWithUnix(t *testing.T) {
 	it.Nil(response)
 }
 
+func Test_ServerWithListeners(t *testing.T) {
+	it := assert.New(t)
+	logger := NewAppLogger("nil", "")
+	config, _ := fakeConfig("application.listeners.yml")
+
+	server := NewAppServer(config, logger)
+	server.GET("/server/listeners", func(ctx *Context) {
+		ctx.SetStatus(http.StatusNotImplemented)
+	})
+
+	go server.Run()
+	for {
+		if len(server.Address()) > 0 {
+			break
+		}
+	}
+	defer os.Remove("/tmp/gogo.listeners.sock")
+
+	addrs := server.Addresses()
+	if !it.Len(addrs, 2) {
+		return
+	}
+	it.Equal(addrs[0], server.Address())
+	it.Equal("/tmp/gogo.listeners.sock", addrs[1])
+
+	// it should work for tcp
+	response, err := http.Get("http://" + addrs[0] + "/server/listeners")
+	if it.Nil(err) {
+		response.Body.Close()
+
+		it.Equal(http.StatusNotImplemented, response.StatusCode)
+	}
+
+	// it should work for unix
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, addr string) (conn net.Conn, err error) {
+				return net.Dial("unix", addrs[1])
+			},
+		},
+	}
+
+	response, err = client.Get("http://unix/server/listeners")
+	if it.Nil(err) {
+		response.Body.Close()
+
+		it.Equal(http.StatusNotImplemented, response.StatusCode)
+	}
+}
+
+func Test_ServerWithSslCerts(t *testing.T) {
+	it := assert.New(t)
+	logger := NewAppLogger("nil", "")
+	config, _ := fakeConfig("application.ssl.yml")
+
+	server := NewAppServer(config, logger)
+	server.GET("/server/ssl", func(ctx *Context) {
+		ctx.SetStatus(http.StatusNotImplemented)
+	})
+
+	go server.Run()
+	for {
+		if len(server.Address()) > 0 {
+			break
+		}
+	}
+
+	testCases := map[string]string{
+		"a.example.com":     "a.example.com",
+		"www.b.example.com": "*.b.example.com",
+	}
+
+	for name, expected := range testCases {
+		client := &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					ServerName:         name,
+					InsecureSkipVerify: true,
+				},
+			},
+		}
+
+		response, err := client.Get("https://" + server.Address() + "/server/ssl")
+		if it.Nil(err) {
+			response.Body.Close()
+
+			it.Equal(http.StatusNotImplemented, response.StatusCode)
+			it.Equal(expected, response.TLS.PeerCertificates[0].Subject.CommonName)
+		}
+	}
+
+	// it should keep serving after reload
+	server.Reload()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true,
+			},
+		},
+	}
+
+	response, err := client.Get("https://" + server.Address() + "/server/ssl")
+	if it.Nil(err) {
+		response.Body.Close()
+
+		it.Equal(http.StatusNotImplemented, response.StatusCode)
+	}
+}
+
+func Test_ServerWithMutualTLS(t *testing.T) {
+	it := assert.New(t)
+	logger := NewAppLogger("nil", "")
+	config, _ := fakeConfig("application.mtls.yml")
+	config.interceptors = &InterceptorConfig{
+		"mtls": map[string]interface{}{
+			"sans": []string{"*.svc.local"},
+		},
+	}
+
+	server := NewAppServer(config, logger)
+	server.GET("/server/mtls", func(ctx *Context) {
+		id := ctx.ClientIdentity()
+		if id == nil {
+			ctx.SetStatus(http.StatusUnauthorized)
+			return
+		}
+
+		ctx.Text(id.CommonName)
+	})
+
+	go server.Run()
+	for {
+		if len(server.Address()) > 0 {
+			break
+		}
+	}
+
+	endpoint := "https://" + server.Address() + "/server/mtls"
+
+	// it should be unauthorized without client certificate
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true,
+			},
+		},
+	}
+
+	response, err := client.Get(endpoint)
+	if it.Nil(err) {
+		response.Body.Close()
+
+		it.Equal(http.StatusUnauthorized, response.StatusCode)
+	}
+
+	// it should work with client certificate
+	cert, err := tls.LoadX509KeyPair("testdata/ssl/client.crt", "testdata/ssl/client.key")
+	if !it.Nil(err) {
+		return
+	}
+
+	client = &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				Certificates:       []tls.Certificate{cert},
+				InsecureSkipVerify: true,
+			},
+		},
+	}
+
+	response, err = client.Get(endpoint)
+	if it.Nil(err) {
+		defer response.Body.Close()
+
+		it.Equal(http.StatusOK, response.StatusCode)
+
+		data, _ := ioutil.ReadAll(response.Body)
+		it.Equal("client.svc.local", string(data))
+	}
+}
+
+func Test_ServerWithH2C(t *testing.T) {
+	it := assert.New(t)
+	logger := NewAppLogger("nil", "")
+	config, _ := fakeConfig("application.h2c.yml")
+
+	server := NewAppServer(config, logger)
+	server.GET("/server/h2c", func(ctx *Context) {
+		ctx.Text(ctx.Request.Proto)
+	})
+
+	go server.Run()
+	for {
+		if len(server.Address()) > 0 {
+			break
+		}
+	}
+
+	endpoint := "http://" + server.Address() + "/server/h2c"
+
+	// it should work with prior knowledge
+	client := &http.Client{
+		Transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+				return net.Dial(network, addr)
+			},
+		},
+	}
+
+	response, err := client.Get(endpoint)
+	if it.Nil(err) {
+		defer response.Body.Close()
+
+		data, _ := ioutil.ReadAll(response.Body)
+		it.Equal("HTTP/2.0", string(data))
+	}
+
+	// it should work for http/1.1
+	response, err = http.Get(endpoint)
+	if it.Nil(err) {
+		defer response.Body.Close()
+
+		data, _ := ioutil.ReadAll(response.Body)
+		it.Equal("HTTP/1.1", string(data))
+	}
+}
+
+func Test_ServerWithH2CDraining(t *testing.T) {
+	it := assert.New(t)
+	logger := NewAppLogger("nil", "")
+	config, _ := fakeConfig("application.h2c.yml")
+
+	started := make(chan struct{})
+
+	server := NewAppServer(config, logger)
+	server.GET("/server/h2c/draining", func(ctx *Context) {
+		close(started)
+
+		time.Sleep(300 * time.Millisecond)
+
+		ctx.Text("DRAINED")
+	})
+
+	err := server.Start(context.Background())
+	if !it.Nil(err) {
+		return
+	}
+
+	endpoint := "http://" + server.Address() + "/server/h2c/draining"
+
+	client := &http.Client{
+		Transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+				return net.Dial(network, addr)
+			},
+		},
+	}
+
+	respc := make(chan string, 1)
+	go func() {
+		response, err := client.Get(endpoint)
+		if err != nil {
+			respc <- err.Error()
+			return
+		}
+		defer response.Body.Close()
+
+		data, _ := ioutil.ReadAll(response.Body)
+		respc <- response.Proto + " " + string(data)
+	}()
+
+	<-started
+
+	// it should wait for h2c stream in flight
+	stopc := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		stopc <- server.Stop(ctx)
+	}()
+
+	select {
+	case <-stopc:
+		t.Fatal("Expected Stop to wait for h2c stream in flight")
+
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	it.Equal("HTTP/2.0 DRAINED", <-respc)
+	it.Nil(<-stopc)
+}
+
+func Test_ServerWithProxyProtocol(t *testing.T) {
+	it := assert.New(t)
+	logger := NewAppLogger("nil", "")
+	config, _ := fakeConfig("application.proxy.yml")
+
+	server := NewAppServer(config, logger)
+	server.RequestReceived.PushBackNamed(hooks.NamedHook{
+		Name: "proxy_authority",
+		Apply: func(w http.ResponseWriter, r *http.Request) bool {
+			if header := proxyproto.FromRequest(r); header != nil {
+				w.Header().Set("X-Proxy-Authority", header.Authority())
+			}
+
+			return true
+		},
+	})
+	server.GET("/server/proxy", func(ctx *Context) {
+		ctx.Text(ctx.Request.RemoteAddr)
+	})
+
+	go server.Run()
+	for {
+		if len(server.Address()) > 0 {
+			break
+		}
+	}
+
+	endpoint := "http://" + server.Address() + "/server/proxy"
+
+	newClient := func(header []byte) *http.Client {
+		return &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+					conn, err := net.Dial(network, addr)
+					if err != nil {
+						return nil, err
+					}
+
+					_, err = conn.Write(header)
+					if err != nil {
+						conn.Close()
+						return nil, err
+					}
+
+					return conn, nil
+				},
+			},
+		}
+	}
+
+	// it should work with v1
+	response, err := newClient([]byte("PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\n")).Get(endpoint)
+	if it.Nil(err) {
+		defer response.Body.Close()
+
+		data, _ := ioutil.ReadAll(response.Body)
+		it.Equal("192.168.0.1:56324", string(data))
+		it.Empty(response.Header.Get("X-Proxy-Authority"))
+	}
+
+	// it should work with v2 and TLVs
+	v2 := []byte("\r\n\r\n\x00\r\nQUIT\n\x21\x11\x00\x1a")
+	v2 = append(v2, 10, 0, 0, 1, 10, 0, 0, 2, 0xdc, 0x04, 0x01, 0xbb)
+	v2 = append(v2, proxyproto.TypeAuthority, 0x00, 0x0b)
+	v2 = append(v2, "example.com"...)
+
+	response, err = newClient(v2).Get(endpoint)
+	if it.Nil(err) {
+		defer response.Body.Close()
+
+		data, _ := ioutil.ReadAll(response.Body)
+		it.Equal("10.0.0.1:56324", string(data))
+		it.Equal("example.com", response.Header.Get("X-Proxy-Authority"))
+	}
+
+	// it should work without header
+	response, err = http.Get(endpoint)
+	if it.Nil(err) {
+		defer response.Body.Close()
+
+		data, _ := ioutil.ReadAll(response.Body)
+		it.Contains(string(data), "127.0.0.1:")
+	}
+}
+
+func Test_ServerWithConnLimits(t *testing.T) {
+	it := assert.New(t)
+	logger := NewAppLogger("nil", "")
+	config, _ := fakeConfig("application.limits.yml")
+
+	server := NewAppServer(config, logger)
+	server.GET("/server/limits", func(ctx *Context) {
+		ctx.SetStatus(http.StatusNoContent)
+	})
+
+	err := server.Start(context.Background())
+	if !it.Nil(err) {
+		return
+	}
+	defer server.Stop(context.Background())
+
+	// it should keep alive connection
+	client := &http.Client{
+		Transport: &http.Transport{},
+	}
+
+	response, err := client.Get("http://" + server.Address() + "/server/limits")
+	if it.Nil(err) {
+		response.Body.Close()
+
+		it.Equal(http.StatusNoContent, response.StatusCode)
+	}
+
+	// it should reject connections exceeded max conns per ip
+	conn, err := net.Dial("tcp", server.Address())
+	if it.Nil(err) {
+		defer conn.Close()
+
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+
+		_, err = conn.Read(make([]byte, 1))
+		it.NotNil(err)
+	}
+
+	stats := server.ListenerStats()
+	if it.Len(stats, 1) {
+		it.Equal(int64(1), stats[0].Accepted)
+		it.Equal(int64(1), stats[0].Rejected)
+		it.Equal(int64(1), stats[0].Active)
+	}
+}
+
 var benchmarkServerWithUnix sync.Once
 
 func Benchmark_ServerWithUnix(b *testing.B) {
@@ -2,18 +2,23 @@ package gogo
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
 	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 	"testing"
 	"time"
 
+	"github.com/dolab/gogo/internal/listeners"
 	"github.com/dolab/httptesting"
 	"github.com/golib/assert"
 )
@@ -319,6 +324,288 @@ func Test_Server_ShutdownWithGhost(t *testing.T) {
 	}
 }
 
+func Test_Server_ShutdownWithDraining(t *testing.T) {
+	it := assert.New(t)
+
+	server := fakeTimeoutServer()
+	server.GET("/server/shutdown/draining", func(ctx *Context) {
+		time.Sleep(300 * time.Millisecond)
+
+		ctx.Text("DRAINED")
+	})
+
+	var shutdowns []string
+	server.RegisterOnShutdown(func() {
+		shutdowns = append(shutdowns, "first")
+	})
+	server.RegisterOnShutdown(func() {
+		shutdowns = append(shutdowns, "second")
+	})
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- server.Serve()
+	}()
+	for {
+		if len(server.Address()) > 0 {
+			break
+		}
+	}
+
+	endpoint := "http://" + server.Address() + "/server/shutdown/draining"
+
+	// issue in-flight request before shutdown
+	respc := make(chan string, 1)
+	go func() {
+		response, err := http.Get(endpoint)
+		if err != nil {
+			respc <- err.Error()
+			return
+		}
+		defer response.Body.Close()
+
+		data, _ := ioutil.ReadAll(response.Body)
+		respc <- string(data)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	server.Shutdown()
+
+	// it should drain in-flight request
+	it.Equal("DRAINED", <-respc)
+
+	// it should return without error
+	it.Nil(<-errc)
+	it.True(server.isDraining())
+	it.Equal([]string{"first", "second"}, shutdowns)
+
+	// it should refuse new connections
+	_, err := http.Get(endpoint)
+	it.NotNil(err)
+}
+
+func Test_Server_serveSignals(t *testing.T) {
+	it := assert.New(t)
+
+	server := fakeServer()
+
+	signals, reload, upgrade, err := server.serveSignals()
+	if it.Nil(err) {
+		it.Equal([]os.Signal{os.Interrupt, syscall.SIGTERM, syscall.SIGHUP}, signals)
+		it.Equal(syscall.SIGHUP, reload)
+		it.Nil(upgrade)
+	}
+
+	// it should not reload by signal of shutdown
+	logger := fakeLogger()
+	config, _ := fakeConfig("application.signals.yml")
+
+	server = NewAppServer(config, logger)
+
+	signals, reload, upgrade, err = server.serveSignals()
+	if it.Nil(err) {
+		it.Equal([]os.Signal{os.Interrupt, syscall.SIGHUP, syscall.SIGQUIT}, signals)
+		it.Nil(reload)
+		it.Equal(syscall.SIGQUIT, upgrade)
+	}
+}
+
+func Test_Server_StartAndStop(t *testing.T) {
+	it := assert.New(t)
+
+	server := fakeTimeoutServer()
+	server.GET("/server/start", func(ctx *Context) {
+		ctx.Text("STARTED")
+	})
+
+	// it should error without started
+	it.Equal(ErrServerNotStarted, server.Wait())
+	it.Equal(ErrServerNotStarted, server.Stop(context.Background()))
+
+	err := server.Start(context.Background())
+	if !it.Nil(err) {
+		return
+	}
+
+	// it should be listening after started
+	response, err := http.Get("http://" + server.Address() + "/server/start")
+	if it.Nil(err) {
+		data, _ := ioutil.ReadAll(response.Body)
+		response.Body.Close()
+
+		it.Equal("STARTED", string(data))
+	}
+
+	// it should error for starting twice
+	it.Equal(ErrServerStarted, server.Start(context.Background()))
+
+	waitc := make(chan error, 1)
+	go func() {
+		waitc <- server.Wait()
+	}()
+
+	it.Nil(server.Stop(context.Background()))
+	it.Nil(<-waitc)
+
+	// it should be ok for stopping twice
+	it.Nil(server.Stop(context.Background()))
+}
+
+func Test_Server_StartWithSdNotify(t *testing.T) {
+	it := assert.New(t)
+
+	name := "@gogo-server-notify-" + strconv.Itoa(os.Getpid())
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: name, Net: "unixgram"})
+	if !it.Nil(err) {
+		return
+	}
+	defer conn.Close()
+
+	os.Setenv(listeners.EnvSdNotifySocket, name)
+	os.Setenv(listeners.EnvSdWatchdogUsec, "20000")
+	defer func() {
+		os.Unsetenv(listeners.EnvSdNotifySocket)
+		os.Unsetenv(listeners.EnvSdWatchdogUsec)
+	}()
+
+	read := func() string {
+		buf := make([]byte, 128)
+
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			return err.Error()
+		}
+
+		return string(buf[:n])
+	}
+
+	server := fakeTimeoutServer()
+
+	err = server.Start(context.Background())
+	if !it.Nil(err) {
+		return
+	}
+
+	// it should notify ready with main pid
+	it.Equal(fmt.Sprintf("READY=1\nMAINPID=%d", os.Getpid()), read())
+
+	// it should ping watchdog
+	it.Equal("WATCHDOG=1", read())
+
+	it.Nil(server.Stop(context.Background()))
+
+	// it should notify stopping
+	for i := 0; i < 10; i++ {
+		if state := read(); state != "WATCHDOG=1" {
+			it.Equal("STOPPING=1", state)
+			break
+		}
+	}
+}
+
+func Test_Server_StartWithListenError(t *testing.T) {
+	it := assert.New(t)
+
+	server := fakeTimeoutServer()
+
+	err := server.Start(context.Background())
+	if !it.Nil(err) {
+		return
+	}
+	defer server.Stop(context.Background())
+
+	// it should return *ListenError for address in use
+	other := NewAppServer(server.config, fakeLogger())
+
+	err = other.Start(context.Background())
+	if it.NotNil(err) {
+		lerr, ok := err.(*ListenError)
+		if it.True(ok) {
+			it.Equal("tcp", lerr.Network)
+			it.Contains(lerr.Error(), "address already in use")
+		}
+
+		it.Equal(ErrServerNotStarted, other.Wait())
+	}
+
+	// it should start by retrying after address released
+	it.Nil(server.Stop(context.Background()))
+
+	err = other.Start(context.Background())
+	if it.Nil(err) {
+		it.NotEmpty(other.Address())
+		it.Nil(other.Stop(context.Background()))
+		it.Nil(other.Wait())
+	}
+}
+
+func Test_Server_StartWithCanceled(t *testing.T) {
+	it := assert.New(t)
+
+	server := fakeTimeoutServer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	it.Equal(context.Canceled, server.Start(ctx))
+	it.Empty(server.Address())
+}
+
+func Test_newAppListenerWithTLSError(t *testing.T) {
+	it := assert.New(t)
+
+	testCases := map[string]*ListenerConfig{
+		"certificates": {
+			Addr:    "127.0.0.1",
+			Ssl:     true,
+			SslCert: "testdata/ssl/missing.crt",
+			SslKey:  "testdata/ssl/missing.key",
+		},
+		"client auth": {
+			Addr:          "127.0.0.1",
+			Ssl:           true,
+			SslCert:       "testdata/ssl/a.example.com.crt",
+			SslKey:        "testdata/ssl/a.example.com.key",
+			SslClientAuth: "unknown",
+		},
+		"client ca": {
+			Addr:        "127.0.0.1",
+			Ssl:         true,
+			SslCert:     "testdata/ssl/a.example.com.crt",
+			SslKey:      "testdata/ssl/a.example.com.key",
+			SslClientCA: "testdata/ssl/missing.crt",
+		},
+		"client auth without ca": {
+			Addr:          "127.0.0.1",
+			Ssl:           true,
+			SslCert:       "testdata/ssl/a.example.com.crt",
+			SslKey:        "testdata/ssl/a.example.com.key",
+			SslClientAuth: "require",
+		},
+	}
+
+	for name, config := range testCases {
+		_, err := newAppListener(config, http.NotFoundHandler())
+		if it.NotNil(err, name) {
+			_, ok := err.(*TLSError)
+			it.True(ok, name)
+		}
+	}
+}
+
+func Test_newAppListenerWithoutProxyTrusted(t *testing.T) {
+	it := assert.New(t)
+
+	_, err := newAppListener(&ListenerConfig{
+		Addr:          "127.0.0.1",
+		ProxyProtocol: true,
+	}, http.NotFoundHandler())
+	it.Equal(ErrProxyUntrusted, err)
+}
+
 // func Test_ServerWithMethodNotAllowed(t *testing.T) {
 // 	server := fakeServer()
 // 	server.HEAD("/server/method/not/allowed", func(ctx *Context) {
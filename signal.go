@@ -0,0 +1,48 @@
+package gogo
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+)
+
+var (
+	// signalNames maps signal name to os.Signal, both "SIGTERM" and "TERM" are valid.
+	signalNames = map[string]os.Signal{
+		"SIGHUP":  syscall.SIGHUP,
+		"SIGINT":  syscall.SIGINT,
+		"SIGQUIT": syscall.SIGQUIT,
+		"SIGTERM": syscall.SIGTERM,
+	}
+)
+
+// ParseSignal returns os.Signal of name given, the name is case insensitive
+// and the SIG prefix is optional, e.g. SIGTERM, sigterm and TERM are the same.
+func ParseSignal(name string) (os.Signal, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+
+	sig, ok := signalNames[name]
+	if !ok {
+		return nil, fmt.Errorf("Unsupported signal %q", name)
+	}
+
+	return sig, nil
+}
+
+// ParseSignals is a shortcut of ParseSignal for names.
+func ParseSignals(names []string) (signals []os.Signal, err error) {
+	for _, name := range names {
+		sig, err := ParseSignal(name)
+		if err != nil {
+			return nil, err
+		}
+
+		signals = append(signals, sig)
+	}
+
+	return
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+// +build !windows
+
+package gogo
+
+import (
+	"syscall"
+)
+
+func init() {
+	signalNames["SIGUSR1"] = syscall.SIGUSR1
+	signalNames["SIGUSR2"] = syscall.SIGUSR2
+}
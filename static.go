@@ -0,0 +1,267 @@
+package gogo
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+)
+
+// StaticOptions defines options of serving files by AppGroup.StaticFS
+type StaticOptions struct {
+	Index         string            // served for directories, default to index.html
+	Fallback      string            // served for files and directory indexes not found, e.g. /index.html for single-page apps
+	Browse        bool              // lists directories without index, default to false
+	Precompressed bool              // serves .br and .gz variants of files if client accepts
+	CacheControl  map[string]string // Cache-Control by extension of files, e.g. .js, and * for others
+}
+
+// precompressedEncodings defines encodings of precompressed files in order of preference
+var precompressedEncodings = []struct {
+	encoding  string
+	extension string
+}{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// staticFiles serves files of http.FileSystem with options
+type staticFiles struct {
+	group *AppGroup
+	fs    http.FileSystem
+	opts  StaticOptions
+}
+
+func newStaticFiles(group *AppGroup, fs http.FileSystem, opts *StaticOptions) *staticFiles {
+	files := &staticFiles{
+		group: group,
+		fs:    fs,
+	}
+	if opts != nil {
+		files.opts = *opts
+	}
+
+	if files.opts.Index == "" {
+		files.opts.Index = "index.html"
+	}
+	if files.opts.Fallback != "" {
+		files.opts.Fallback = path.Clean("/" + files.opts.Fallback)
+	}
+
+	return files
+}
+
+// Serve implements Middleware for files
+func (s *staticFiles) Serve(ctx *Context) {
+	w, r := ctx.Response, ctx.Request
+
+	name := path.Clean("/" + ctx.Params.Get("filepath"))
+
+	f, info, err := s.open(name)
+	if err != nil && os.IsNotExist(err) && s.opts.Fallback != "" {
+		name = s.opts.Fallback
+
+		f, info, err = s.open(name)
+	}
+	if err != nil {
+		s.serveError(w, r, err)
+		return
+	}
+
+	if info.IsDir() {
+		f.Close()
+
+		// redirect to canonical path of directory for relative links
+		if !strings.HasSuffix(r.URL.Path, "/") {
+			location := path.Base(r.URL.Path) + "/"
+			if r.URL.RawQuery != "" {
+				location += "?" + r.URL.RawQuery
+			}
+
+			w.Header().Set("Location", location)
+			w.WriteHeader(http.StatusMovedPermanently)
+			return
+		}
+
+		index := path.Join(name, s.opts.Index)
+
+		f, info, err = s.open(index)
+		if err != nil && os.IsNotExist(err) {
+			if s.opts.Browse {
+				s.browse(w, r, name)
+				return
+			}
+
+			// NOTE: directory without index is served by fallback as missing files
+			if s.opts.Fallback != "" {
+				index = s.opts.Fallback
+
+				f, info, err = s.open(index)
+			}
+		}
+		if err != nil {
+			s.serveError(w, r, err)
+			return
+		}
+		if info.IsDir() {
+			f.Close()
+
+			s.group.serveNotFound(w, r)
+			return
+		}
+
+		name = index
+	}
+	defer f.Close()
+
+	header := w.Header()
+	if value := s.cacheControl(name); value != "" {
+		header.Set("Cache-Control", value)
+	}
+
+	if s.opts.Precompressed {
+		header.Add("Vary", "Accept-Encoding")
+
+		if cf, cinfo, encoding := s.openPrecompressed(r, name); cf != nil {
+			defer cf.Close()
+
+			// NOTE: type of the uncompressed file is sent as what the plain path does
+			if _, ok := header["Content-Type"]; !ok {
+				contentType, err := s.contentType(name, f)
+				if err != nil {
+					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+					return
+				}
+
+				header.Set("Content-Type", contentType)
+			}
+			header.Set("Content-Encoding", encoding)
+
+			http.ServeContent(w, r, name, cinfo.ModTime(), cf)
+			return
+		}
+	}
+
+	http.ServeContent(w, r, name, info.ModTime(), f)
+}
+
+// open returns file and its info of name
+func (s *staticFiles) open(name string) (http.File, os.FileInfo, error) {
+	f, err := s.fs.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+
+		return nil, nil, err
+	}
+
+	return f, info, nil
+}
+
+// openPrecompressed returns precompressed variant of name accepted by request,
+// it returns nil if no variant found.
+func (s *staticFiles) openPrecompressed(r *http.Request, name string) (http.File, os.FileInfo, string) {
+	accepted := acceptedEncodings(r.Header.Get("Accept-Encoding"))
+
+	for _, variant := range precompressedEncodings {
+		if !accepted[variant.encoding] {
+			continue
+		}
+
+		f, info, err := s.open(name + variant.extension)
+		if err != nil {
+			continue
+		}
+		if info.IsDir() {
+			f.Close()
+			continue
+		}
+
+		return f, info, variant.encoding
+	}
+
+	return nil, nil, ""
+}
+
+// contentType returns Content-Type of name the same as http.ServeContent, content
+// of f is sniffed if the extension is unknown.
+func (s *staticFiles) contentType(name string, f http.File) (string, error) {
+	if contentType := mime.TypeByExtension(path.Ext(name)); contentType != "" {
+		return contentType, nil
+	}
+
+	var buf [512]byte
+
+	n, _ := io.ReadFull(f, buf[:])
+
+	_, err := f.Seek(0, io.SeekStart)
+	if err != nil {
+		return "", err
+	}
+
+	return http.DetectContentType(buf[:n]), nil
+}
+
+// browse lists files of directory
+func (s *staticFiles) browse(w http.ResponseWriter, r *http.Request, name string) {
+	req := r.Clone(r.Context())
+	req.URL.Path = strings.TrimSuffix(name, "/") + "/"
+
+	http.FileServer(s.fs).ServeHTTP(w, req)
+}
+
+// cacheControl returns Cache-Control of name by its extension
+func (s *staticFiles) cacheControl(name string) string {
+	if len(s.opts.CacheControl) == 0 {
+		return ""
+	}
+
+	if value, ok := s.opts.CacheControl[strings.ToLower(path.Ext(name))]; ok {
+		return value
+	}
+
+	return s.opts.CacheControl["*"]
+}
+
+// serveError responds error of opening files
+func (s *staticFiles) serveError(w http.ResponseWriter, r *http.Request, err error) {
+	if os.IsPermission(err) {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	s.group.serveNotFound(w, r)
+}
+
+// acceptedEncodings returns encodings accepted of Accept-Encoding header,
+// encodings with q=0 are excluded.
+func acceptedEncodings(header string) map[string]bool {
+	encodings := make(map[string]bool)
+
+	for _, value := range strings.Split(header, ",") {
+		var quality string
+
+		if i := strings.IndexByte(value, ';'); i != -1 {
+			value, quality = value[:i], strings.TrimSpace(value[i+1:])
+		}
+
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+
+		if strings.HasPrefix(quality, "q=") && strings.Trim(quality[2:], "0.") == "" {
+			continue
+		}
+
+		encodings[value] = true
+	}
+
+	return encodings
+}
@@ -0,0 +1,221 @@
+package gogo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/golib/assert"
+)
+
+var fakeStaticFS = http.FS(fstest.MapFS{
+	"index.html":      {Data: []byte("<html>index</html>")},
+	"app.js":          {Data: []byte("console.log('app')")},
+	"app.js.br":       {Data: []byte("br:app.js")},
+	"app.js.gz":       {Data: []byte("gz:app.js")},
+	"docs/index.html": {Data: []byte("<html>docs</html>")},
+	"images/logo.svg": {Data: []byte("<svg></svg>")},
+	"notes":           {Data: []byte("plain notes")},
+	"notes.gz":        {Data: []byte("gz:notes")},
+})
+
+func Test_GroupStatic(t *testing.T) {
+	it := assert.New(t)
+	server := fakeServer()
+
+	group := server.NewGroup("/v1", func(ctx *Context) {
+		ctx.SetHeader("X-Static-Filter", "true")
+		ctx.Next()
+	})
+	group.StaticFS("/assets", fakeStaticFS, &StaticOptions{
+		CacheControl: map[string]string{
+			".js": "max-age=31536000, immutable",
+			"*":   "no-cache",
+		},
+	})
+
+	testCases := []struct {
+		path         string
+		code         int
+		body         string
+		cacheControl string
+	}{
+		{"/v1/assets/app.js", http.StatusOK, "console.log('app')", "max-age=31536000, immutable"},
+		{"/v1/assets/", http.StatusOK, "<html>index</html>", "no-cache"},
+		{"/v1/assets/docs/", http.StatusOK, "<html>docs</html>", "no-cache"},
+		{"/v1/assets/images/", http.StatusNotFound, "", ""},
+		{"/v1/assets/missing.js", http.StatusNotFound, "", ""},
+	}
+	for _, testCase := range testCases {
+		r, _ := http.NewRequest(http.MethodGet, testCase.path, nil)
+		w := httptest.NewRecorder()
+
+		server.ServeHTTP(w, r)
+		it.Equal(testCase.code, w.Code, testCase.path)
+		it.Equal("true", w.Header().Get("X-Static-Filter"), testCase.path)
+		it.Equal(testCase.cacheControl, w.Header().Get("Cache-Control"), testCase.path)
+		if testCase.code == http.StatusOK {
+			it.Equal(testCase.body, w.Body.String(), testCase.path)
+		}
+	}
+
+	// redirect for directory
+	r, _ := http.NewRequest(http.MethodGet, "/v1/assets/docs", nil)
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusMovedPermanently, w.Code)
+	it.Equal("docs/", w.Header().Get("Location"))
+
+	// it should not be served without prefix of group
+	r, _ = http.NewRequest(http.MethodGet, "/assets/app.js", nil)
+	w = httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusNotFound, w.Code)
+
+	// it should serve HEAD without body
+	r, _ = http.NewRequest(http.MethodHead, "/v1/assets/app.js", nil)
+	w = httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusOK, w.Code)
+	it.Equal("true", w.Header().Get("X-Static-Filter"))
+	it.Equal("max-age=31536000, immutable", w.Header().Get("Cache-Control"))
+	it.Contains(w.Header().Get("Content-Type"), "javascript")
+	it.Empty(w.Body.String())
+
+	routes := server.Routes()
+	if it.Len(routes, 2) {
+		for i, method := range []string{http.MethodGet, http.MethodHead} {
+			it.Equal(method, routes[i].Method)
+			it.Equal("/v1/assets/*filepath", routes[i].Path)
+			it.Equal("Static", routes[i].Action)
+			it.Equal(1, routes[i].Middlewares)
+		}
+	}
+}
+
+func Test_GroupStaticWithRouteOptions(t *testing.T) {
+	it := assert.New(t)
+	server := fakeServer()
+
+	server.StaticFS("/assets", fakeStaticFS, nil, RouteName("assets"), RouteMeta("owner", "web"))
+
+	uri, err := server.URLFor("assets", "app.js")
+	if it.Nil(err) {
+		it.Equal("/assets/app.js", uri)
+	}
+
+	var owner string
+	server.RequestRouted.PushBack(func(w http.ResponseWriter, r *http.Request) bool {
+		owner = RouteFromRequest(r).Meta.String("owner")
+		return true
+	})
+
+	for _, method := range []string{http.MethodGet, http.MethodHead} {
+		owner = ""
+
+		r, _ := http.NewRequest(method, "/assets/app.js", nil)
+		w := httptest.NewRecorder()
+
+		server.ServeHTTP(w, r)
+		it.Equal(http.StatusOK, w.Code, method)
+		it.Equal("web", owner, method)
+	}
+}
+
+func Test_GroupStaticWithFallback(t *testing.T) {
+	it := assert.New(t)
+	server := fakeServer()
+
+	server.StaticFS("/app", fakeStaticFS, &StaticOptions{
+		Fallback: "index.html",
+		Browse:   true,
+	})
+
+	for _, path := range []string{"/app/", "/app/orders/1", "/app/missing.js"} {
+		r, _ := http.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+
+		server.ServeHTTP(w, r)
+		it.Equal(http.StatusOK, w.Code, path)
+		it.Equal("<html>index</html>", w.Body.String(), path)
+	}
+
+	// directory listing
+	r, _ := http.NewRequest(http.MethodGet, "/app/images/", nil)
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusOK, w.Code)
+	it.Contains(w.Body.String(), "logo.svg")
+
+	// it should fall back for directory without index if browse is off
+	server.StaticFS("/spa", fakeStaticFS, &StaticOptions{
+		Fallback: "index.html",
+	})
+
+	r, _ = http.NewRequest(http.MethodGet, "/spa/images/", nil)
+	w = httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusOK, w.Code)
+	it.Equal("<html>index</html>", w.Body.String())
+}
+
+func Test_GroupStaticWithPrecompressed(t *testing.T) {
+	it := assert.New(t)
+	server := fakeServer()
+
+	server.StaticFS("/assets", fakeStaticFS, &StaticOptions{
+		Precompressed: true,
+	})
+
+	testCases := []struct {
+		acceptEncoding  string
+		contentEncoding string
+		body            string
+	}{
+		{"gzip, deflate, br", "br", "br:app.js"},
+		{"gzip", "gzip", "gz:app.js"},
+		{"br;q=0, gzip;q=0.5", "gzip", "gz:app.js"},
+		{"", "", "console.log('app')"},
+	}
+	for _, testCase := range testCases {
+		r, _ := http.NewRequest(http.MethodGet, "/assets/app.js", nil)
+		if testCase.acceptEncoding != "" {
+			r.Header.Set("Accept-Encoding", testCase.acceptEncoding)
+		}
+		w := httptest.NewRecorder()
+
+		server.ServeHTTP(w, r)
+		it.Equal(http.StatusOK, w.Code)
+		it.Equal(testCase.contentEncoding, w.Header().Get("Content-Encoding"))
+		it.Contains(w.Header().Get("Content-Type"), "javascript")
+		it.Equal("Accept-Encoding", w.Header().Get("Vary"))
+		it.Equal(testCase.body, w.Body.String())
+	}
+
+	// no variant
+	r, _ := http.NewRequest(http.MethodGet, "/assets/images/logo.svg", nil)
+	r.Header.Set("Accept-Encoding", "gzip, br")
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusOK, w.Code)
+	it.Empty(w.Header().Get("Content-Encoding"))
+	it.Equal("<svg></svg>", w.Body.String())
+
+	// it should sniff type of the uncompressed file
+	r, _ = http.NewRequest(http.MethodGet, "/assets/notes", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w = httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusOK, w.Code)
+	it.Equal("gzip", w.Header().Get("Content-Encoding"))
+	it.Equal("text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+	it.Equal("gz:notes", w.Body.String())
+}
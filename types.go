@@ -1,8 +1,10 @@
 package gogo
 
 import (
+	"context"
 	"net/http"
 	"net/http/httputil"
+	"time"
 
 	"github.com/dolab/gogo/pkgs/interceptors"
 	"github.com/dolab/httpdispatch"
@@ -28,29 +30,58 @@ type Configer interface {
 	LoadInterceptors() error
 }
 
+// A ReloadableConfiger represents config supports reloading interceptors,
+// interceptors are reloaded only if Configer implements it.
+type ReloadableConfiger interface {
+	Configer
+
+	InterceptorsFilename() string
+	SetInterceptors(config interceptors.Configer)
+}
+
 // A Grouper represents router interface
 type Grouper interface {
 	NewGroup(prefix string, filters ...Middleware) Grouper
+	NewVersionedGroup(prefix string, policy VersionPolicy, filters ...Middleware) *VersionedGroup
 	SetHandler(handler Handler)
+	SetNotFound(handler http.Handler)
+	SetMethodNotAllowed(handler http.Handler)
+	SetAutoOptions(cors *CORSConfig)
+	SetTimeout(timeout time.Duration)
 	Use(filters ...Middleware)
-	OPTIONS(uri string, filter Middleware)
-	HEAD(uri string, filter Middleware)
-	POST(uri string, filter Middleware)
-	GET(uri string, filter Middleware)
-	PUT(uri string, filter Middleware)
-	PATCH(uri string, filter Middleware)
-	DELETE(uri string, filter Middleware)
-	Any(uri string, filter Middleware)
+	OPTIONS(uri string, filter Middleware, opts ...RouteOption)
+	HEAD(uri string, filter Middleware, opts ...RouteOption)
+	POST(uri string, filter Middleware, opts ...RouteOption)
+	GET(uri string, filter Middleware, opts ...RouteOption)
+	PUT(uri string, filter Middleware, opts ...RouteOption)
+	PATCH(uri string, filter Middleware, opts ...RouteOption)
+	DELETE(uri string, filter Middleware, opts ...RouteOption)
+	Any(uri string, filter Middleware, opts ...RouteOption)
 	Static(uri, root string)
-	Resource(uri string, resource interface{}) Grouper
+	StaticFS(uri string, fs http.FileSystem, opts *StaticOptions, routeOpts ...RouteOption)
+	Resource(uri string, resource interface{}, opts ...RouteOption) Grouper
 	Proxy(method, uri string, proxy *httputil.ReverseProxy)
-	HandlerFunc(method, uri string, fn http.HandlerFunc)
-	Handler(method, uri string, handler http.Handler)
-	Handle(method, uri string, filter Middleware)
+	HandlerFunc(method, uri string, fn http.HandlerFunc, opts ...RouteOption)
+	Handler(method, uri string, handler http.Handler, opts ...RouteOption)
+	Handle(method, uri string, filter Middleware, opts ...RouteOption)
 	MountRPC(method string, rpc RPCServicer)
 	MockHandle(method, uri string, recorder http.ResponseWriter, filter Middleware)
 }
 
+// A Component represents lifecycle-aware component of server, e.g. db pools.
+// Embed NopComponent for components which care about parts of lifecycle only.
+type Component interface {
+	// OnStart is called before listening, server fails to start if error returned.
+	OnStart(ctx context.Context) error
+
+	// OnReady is called after listening and before serving, server fails to
+	// start if error returned.
+	OnReady(ctx context.Context) error
+
+	// OnShutdown is called after all connections drained.
+	OnShutdown(ctx context.Context) error
+}
+
 // A Servicer represents application interface
 type Servicer interface {
 	Init(config Configer, group Grouper)
@@ -0,0 +1,127 @@
+package gogo
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/dolab/gogo/internal/listeners"
+)
+
+// Upgrade forks a new process of the current binary with all listeners handed
+// over, and waits for it reporting ready within upgrade timeout. The caller should
+// drain the current server after Upgrade returns without error.
+//
+// NOTE: Upgrade is not supported on windows.
+func (s *AppServer) Upgrade() error {
+	s.localMux.RLock()
+	locals := s.localListeners
+	s.localMux.RUnlock()
+
+	if len(locals) == 0 {
+		return ErrUpgradeNotServing
+	}
+
+	ifaces := make([]listeners.Interface, len(locals))
+	for i, local := range locals {
+		ifaces[i] = local.listener
+	}
+
+	timeout := DefaultUpgradeTimeout
+	if config := s.config.Section(); config.Server.UpgradeTimeout > 0 {
+		timeout = config.Server.UpgradeTimeout
+	}
+
+	env, files, err := listeners.Export(ifaces...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, file := range files {
+			file.Close()
+		}
+	}()
+
+	rd, wr, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer rd.Close()
+
+	binary, err := os.Executable()
+	if err != nil {
+		wr.Close()
+		return err
+	}
+
+	cmd := exec.Command(binary, os.Args[1:]...)
+	cmd.Env = append(upgradeEnviron(), env...)
+	cmd.Env = append(cmd.Env, listeners.ReadyEnv(len(files)))
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = append(files, wr)
+
+	err = cmd.Start()
+
+	// close write end of parent, so read will return when child exited
+	wr.Close()
+
+	if err != nil {
+		return fmt.Errorf("Upgrade(%s): %v", binary, err)
+	}
+
+	readyc := make(chan error, 1)
+	go func() {
+		data, err := ioutil.ReadAll(rd)
+		if err == nil && len(data) == 0 {
+			err = fmt.Errorf("Upgrade(%s): child process exited without ready", binary)
+		}
+
+		readyc <- err
+	}()
+
+	select {
+	case err = <-readyc:
+		if err == nil {
+			s.loggerNew("GOGO").Infof("Upgraded with child process %d", cmd.Process.Pid)
+
+			atomic.StoreInt32(&s.localUpgraded, 1)
+
+			// release child process which will be adopted by init
+			go cmd.Wait()
+		}
+
+	case <-time.After(time.Duration(timeout) * time.Second):
+		err = ErrUpgradeTimeout
+	}
+
+	if err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}
+
+	return err
+}
+
+// upgradeEnviron returns environ of the current process without inherited settings
+func upgradeEnviron() (environ []string) {
+	for _, env := range os.Environ() {
+		if strings.HasPrefix(env, listeners.EnvListenerFds+"=") || strings.HasPrefix(env, listeners.EnvReadyFd+"=") {
+			continue
+		}
+
+		// watchdog of systemd is bound to main pid, which is the child process after upgraded
+		if strings.HasPrefix(env, listeners.EnvSdWatchdogPid+"=") {
+			continue
+		}
+
+		environ = append(environ, env)
+	}
+
+	return
+}
@@ -0,0 +1,324 @@
+package gogo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolab/gogo/pkgs/errors"
+	"github.com/dolab/httpdispatch"
+)
+
+// versionParam defines name of path param for version resolved from path prefix
+const versionParam = "version"
+
+// A VersionPolicy defines how to resolve API version of requests. Sources are
+// tried in order of path prefix, Accept media type and custom header.
+type VersionPolicy struct {
+	Path    bool   // resolves from path prefix, e.g. /v2/orders
+	Vendor  string // resolves from Accept media type, e.g. app for application/vnd.app.v2+json
+	Header  string // resolves from custom header, e.g. X-API-Version
+	Default string // used if not resolved, default to the first version declared
+}
+
+// A VersionOption defines option of a version when declaring
+type VersionOption func(version *apiVersion)
+
+// VersionSunset sets sunset date of the version, responses of the version are
+// sent with Sunset header, and routes of the version are attached with metadata
+// of sunset.
+func VersionSunset(sunset time.Time) VersionOption {
+	return func(version *apiVersion) {
+		version.sunset = sunset
+	}
+}
+
+// apiVersion defines a version declared of VersionedGroup
+type apiVersion struct {
+	name   string
+	sunset time.Time
+	group  *AppGroup
+}
+
+// VersionedGroup defines routes registered per API version, routes of the same
+// method and path are dispatched by version resolved of request. Routes which
+// are not registered for a version fall back to previous versions in order of
+// declaration.
+type VersionedGroup struct {
+	mux      sync.RWMutex
+	group    *AppGroup
+	policy   VersionPolicy
+	versions []*apiVersion
+	handles  map[string]*versionedHandle
+}
+
+// NewVersionedGroup returns a new *VersionedGroup with prefix and filters, routes
+// are registered by groups of Version.
+//
+// Example:
+//
+//	orders := r.NewVersionedGroup("/orders", VersionPolicy{Vendor: "app", Default: "v1"})
+//	orders.Version("v1", VersionSunset(sunset)).GET("/:order", v1.Show)
+//	orders.Version("v2").GET("/:order", v2.Show)
+func (r *AppGroup) NewVersionedGroup(prefix string, policy VersionPolicy, filters ...Middleware) *VersionedGroup {
+	group := r.NewGroup(prefix, filters...).(*AppGroup)
+	if policy.Path {
+		group.prefix = group.buildPrefix("/:" + versionParam)
+	}
+
+	return &VersionedGroup{
+		group:   group,
+		policy:  policy,
+		handles: make(map[string]*versionedHandle),
+	}
+}
+
+// Version returns group for registering routes of the version, it declares the
+// version if not exists. Version names are matched in case insensitive, and the
+// leading v is optional for requests, e.g. 2 for v2.
+func (vg *VersionedGroup) Version(name string, opts ...VersionOption) Grouper {
+	vg.mux.Lock()
+	defer vg.mux.Unlock()
+
+	for _, version := range vg.versions {
+		if strings.EqualFold(version.name, name) {
+			return version.group
+		}
+	}
+
+	version := &apiVersion{
+		name: name,
+	}
+	for _, opt := range opts {
+		opt(version)
+	}
+
+	group := vg.group.NewGroup("").(*AppGroup)
+	group.handler = &versionRouter{
+		group:   vg,
+		version: version,
+	}
+
+	// NOTE: copy options for avoiding shared backing array between versions
+	options := append([]RouteOption{}, group.options...)
+	options = append(options, RouteMeta("version", name))
+	if !version.sunset.IsZero() {
+		options = append(options, RouteMeta("sunset", version.sunset))
+	}
+	group.options = options
+
+	version.group = group
+
+	vg.versions = append(vg.versions, version)
+
+	return group
+}
+
+// Versions returns names of all versions in order of declaration
+func (vg *VersionedGroup) Versions() []string {
+	vg.mux.RLock()
+	defer vg.mux.RUnlock()
+
+	names := make([]string, len(vg.versions))
+	for i, version := range vg.versions {
+		names[i] = version.name
+	}
+
+	return names
+}
+
+// register adds handler of version for method and path, it registers a
+// dispatcher of versions into routes tree for the first time.
+func (vg *VersionedGroup) register(method, uri string, version *apiVersion, handler httpdispatch.Handler) {
+	vg.mux.Lock()
+	defer vg.mux.Unlock()
+
+	key := method + " " + uri
+
+	handle, ok := vg.handles[key]
+	if !ok {
+		handle = &versionedHandle{
+			group:    vg,
+			handlers: make(map[*apiVersion]httpdispatch.Handler),
+		}
+
+		vg.handles[key] = handle
+		vg.group.handler.Handle(method, uri, handle)
+	}
+
+	handle.handlers[version] = handler
+}
+
+// lookup returns index of version matched name, it returns -1 if not found.
+func (vg *VersionedGroup) lookup(name string) int {
+	for i, version := range vg.versions {
+		if strings.EqualFold(version.name, name) || strings.EqualFold(version.name, "v"+name) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// resolve returns version of request by policy, it returns empty if no version
+// found with default.
+func (vg *VersionedGroup) resolve(r *http.Request, ps httpdispatch.Params) (name string) {
+	if vg.policy.Path {
+		name = ps.ByName(versionParam)
+	}
+
+	if name == "" && vg.policy.Vendor != "" {
+		name = vendorVersion(r.Header.Get("Accept"), vg.policy.Vendor)
+	}
+
+	if name == "" && vg.policy.Header != "" {
+		name = strings.TrimSpace(r.Header.Get(vg.policy.Header))
+	}
+
+	if name == "" {
+		name = vg.policy.Default
+	}
+
+	if name == "" {
+		vg.mux.RLock()
+		if len(vg.versions) > 0 {
+			name = vg.versions[0].name
+		}
+		vg.mux.RUnlock()
+	}
+
+	return
+}
+
+// vendorVersion returns version of vendor media type in accept, e.g. v2 for
+// application/vnd.app.v2+json.
+func vendorVersion(accept, vendor string) string {
+	prefix := "application/vnd." + strings.ToLower(vendor) + "."
+
+	for _, media := range strings.Split(accept, ",") {
+		if i := strings.IndexByte(media, ';'); i != -1 {
+			media = media[:i]
+		}
+
+		media = strings.ToLower(strings.TrimSpace(media))
+		if !strings.HasPrefix(media, prefix) {
+			continue
+		}
+
+		version := media[len(prefix):]
+		if i := strings.IndexByte(version, '+'); i != -1 {
+			version = version[:i]
+		}
+
+		if version != "" {
+			return version
+		}
+	}
+
+	return ""
+}
+
+// versionRouter implements Handler for groups of version, it registers handlers
+// into VersionedGroup.
+type versionRouter struct {
+	group   *VersionedGroup
+	version *apiVersion
+}
+
+// ServeHTTP implements http.Handler by routes tree of VersionedGroup
+func (vr *versionRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	vr.group.group.handler.ServeHTTP(w, r)
+}
+
+// Handle registers handler of the version
+func (vr *versionRouter) Handle(method, uri string, handler httpdispatch.Handler) {
+	vr.group.register(method, uri, vr.version, handler)
+}
+
+// ServeFiles registers files of the version
+func (vr *versionRouter) ServeFiles(uri string, fs http.FileSystem) {
+	vr.group.register(http.MethodGet, uri, vr.version, httpdispatch.NewFileHandle(fs))
+}
+
+// versionedHandle dispatches requests to handlers of the same method and path
+// by version of request.
+type versionedHandle struct {
+	group    *VersionedGroup
+	handlers map[*apiVersion]httpdispatch.Handler
+}
+
+// Handle implements httpdispatch.Handler interface
+func (h *versionedHandle) Handle(w http.ResponseWriter, r *http.Request, ps httpdispatch.Params) {
+	vg := h.group
+
+	if vg.policy.Vendor != "" {
+		w.Header().Add("Vary", "Accept")
+	}
+	if vg.policy.Header != "" {
+		w.Header().Add("Vary", vg.policy.Header)
+	}
+
+	name := vg.resolve(r, ps)
+
+	vg.mux.RLock()
+	var (
+		version *apiVersion
+		handler httpdispatch.Handler
+	)
+	if i := vg.lookup(name); i != -1 {
+		version = vg.versions[i]
+
+		// fall back to previous versions
+		for ; i >= 0 && handler == nil; i-- {
+			handler = h.handlers[vg.versions[i]]
+		}
+	}
+	vg.mux.RUnlock()
+
+	if version == nil {
+		vg.unsupported(w, r, name)
+		return
+	}
+
+	if handler == nil {
+		vg.group.serveNotFound(w, r)
+		return
+	}
+
+	if !version.sunset.IsZero() {
+		w.Header().Set("Sunset", version.sunset.UTC().Format(http.TimeFormat))
+	}
+
+	r = r.WithContext(context.WithValue(r.Context(), ctxVersionKey, version.name))
+
+	handler.Handle(w, r, ps)
+}
+
+// unsupported responds structured error of pkgs/errors for unknown version
+func (vg *VersionedGroup) unsupported(w http.ResponseWriter, r *http.Request, name string) {
+	var requestID string
+	if vg.group.server.hasRequestID() {
+		requestID = r.Header.Get(vg.group.server.requestID)
+	}
+
+	err := errors.NewRequestFailure(
+		errors.New("UnsupportedVersion", fmt.Sprintf("The API version %q is not supported, available versions are %s.", name, strings.Join(vg.Versions(), ", ")), nil),
+		http.StatusBadRequest,
+		requestID,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(err.StatusCode())
+	w.Write([]byte(err.Error()))
+}
+
+// VersionFromRequest returns API version which request dispatched by VersionedGroup,
+// it returns empty for routes without version.
+func VersionFromRequest(r *http.Request) string {
+	version, _ := r.Context().Value(ctxVersionKey).(string)
+
+	return version
+}
@@ -0,0 +1,204 @@
+package gogo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golib/assert"
+)
+
+func Test_VersionedGroup(t *testing.T) {
+	it := assert.New(t)
+	server := fakeServer()
+
+	sunset := time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	orders := server.NewVersionedGroup("/orders", VersionPolicy{
+		Vendor:  "app",
+		Header:  "X-API-Version",
+		Default: "v1",
+	})
+
+	v1 := orders.Version("v1", VersionSunset(sunset))
+	v1.GET("/:order", func(ctx *Context) {
+		ctx.Text("v1:" + ctx.Params.Get("order") + ":" + ctx.Version())
+	})
+	v1.DELETE("/:order", func(ctx *Context) {
+		ctx.Text("v1 deleted")
+	})
+
+	v2 := orders.Version("v2")
+	v2.GET("/:order", func(ctx *Context) {
+		ctx.Text("v2:" + ctx.Params.Get("order") + ":" + ctx.Version())
+	})
+	it.Equal(v2, orders.Version("V2"))
+	it.Equal([]string{"v1", "v2"}, orders.Versions())
+
+	testCases := []struct {
+		method string
+		accept string
+		header string
+		body   string
+		sunset bool
+	}{
+		{http.MethodGet, "", "", "v1:1:v1", true},
+		{http.MethodGet, "application/vnd.app.v2+json", "", "v2:1:v2", false},
+		{http.MethodGet, "text/html, Application/VND.App.V2+json; q=0.9", "", "v2:1:v2", false},
+		{http.MethodGet, "", "2", "v2:1:v2", false},
+		{http.MethodGet, "application/vnd.app.v1+json", "v2", "v1:1:v1", true},
+		{http.MethodDelete, "application/vnd.app.v2+json", "", "v1 deleted", false},
+	}
+	for _, testCase := range testCases {
+		r, _ := http.NewRequest(testCase.method, "/orders/1", nil)
+		if testCase.accept != "" {
+			r.Header.Set("Accept", testCase.accept)
+		}
+		if testCase.header != "" {
+			r.Header.Set("X-API-Version", testCase.header)
+		}
+		w := httptest.NewRecorder()
+
+		server.ServeHTTP(w, r)
+		it.Equal(http.StatusOK, w.Code)
+		it.Equal(testCase.body, w.Body.String())
+		it.Equal([]string{"Accept", "X-API-Version"}, w.Header()["Vary"])
+		if testCase.sunset {
+			it.Equal("Fri, 01 Jan 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+		} else {
+			it.Empty(w.Header().Get("Sunset"))
+		}
+	}
+
+	// metadata of routes
+	versions := map[string]bool{}
+	for _, route := range server.Routes() {
+		if route.Path == "/orders/:order" && route.Method == http.MethodGet {
+			versions[route.Meta.String("version")] = true
+
+			value, ok := route.Meta.Get("sunset")
+			it.Equal(route.Meta.String("version") == "v1", ok)
+			if ok {
+				it.Equal(sunset, value)
+			}
+		}
+	}
+	it.Equal(map[string]bool{"v1": true, "v2": true}, versions)
+}
+
+func Test_VersionedGroupWithPath(t *testing.T) {
+	it := assert.New(t)
+	server := fakeServer()
+
+	api := server.NewVersionedGroup("/api", VersionPolicy{
+		Path: true,
+	})
+	api.Version("v1").GET("/users", func(ctx *Context) {
+		ctx.Text("v1 users")
+	})
+	api.Version("v2").GET("/users", func(ctx *Context) {
+		ctx.Text("v2 users")
+	})
+
+	testCases := map[string]string{
+		"/api/v1/users": "v1 users",
+		"/api/v2/users": "v2 users",
+		"/api/2/users":  "v2 users",
+	}
+	for path, expected := range testCases {
+		r, _ := http.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+
+		server.ServeHTTP(w, r)
+		it.Equal(http.StatusOK, w.Code, path)
+		it.Equal(expected, w.Body.String(), path)
+	}
+}
+
+func Test_VersionedGroupWithUnsupported(t *testing.T) {
+	it := assert.New(t)
+	server := fakeServer()
+
+	orders := server.NewVersionedGroup("/orders", VersionPolicy{
+		Header: "X-API-Version",
+	})
+	orders.Version("v1").GET("/:order", func(ctx *Context) {
+		ctx.Text("v1")
+	})
+	orders.Version("v2").PUT("/:order", func(ctx *Context) {
+		ctx.Text("v2")
+	})
+
+	// default to the first version
+	r, _ := http.NewRequest(http.MethodGet, "/orders/1", nil)
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusOK, w.Code)
+	it.Equal("v1", w.Body.String())
+
+	// unknown version
+	r, _ = http.NewRequest(http.MethodGet, "/orders/1", nil)
+	r.Header.Set("X-API-Version", "v3")
+	r.Header.Set(server.requestID, "gogo-version")
+	w = httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusBadRequest, w.Code)
+	it.Equal("application/json", w.Header().Get("Content-Type"))
+
+	var failure struct {
+		Code    string
+		Message string
+		Extra   string
+	}
+	if it.Nil(json.Unmarshal(w.Body.Bytes(), &failure)) {
+		it.Equal("UnsupportedVersion", failure.Code)
+		it.Contains(failure.Message, `"v3"`)
+		it.Contains(failure.Message, "v1, v2")
+		it.Contains(failure.Extra, "gogo-version")
+	}
+
+	// not registered for the version or previous versions
+	r, _ = http.NewRequest(http.MethodPut, "/orders/1", nil)
+	r.Header.Set("X-API-Version", "v1")
+	w = httptest.NewRecorder()
+
+	server.ServeHTTP(w, r)
+	it.Equal(http.StatusNotFound, w.Code)
+}
+
+func Test_VersionedGroupWithConcurrentVersion(t *testing.T) {
+	it := assert.New(t)
+	server := fakeServer()
+
+	orders := server.NewVersionedGroup("/orders", VersionPolicy{
+		Header: "X-API-Version",
+	})
+	orders.Version("v1").GET("/:order", func(ctx *Context) {
+		ctx.Text("v1")
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		for i := 2; i < 1000; i++ {
+			orders.Version(fmt.Sprintf("v%d", i))
+		}
+	}()
+
+	// it should resolve default version while declaring
+	for i := 0; i < 1000; i++ {
+		r, _ := http.NewRequest(http.MethodGet, "/orders/1", nil)
+		w := httptest.NewRecorder()
+
+		server.ServeHTTP(w, r)
+		it.Equal("v1", w.Body.String())
+	}
+
+	<-done
+}